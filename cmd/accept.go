@@ -15,6 +15,7 @@ func Accept(cmdInfo CommandInfo) {
 		return
 	}
 	userID := cmdInfo.Service.Rep.GetUser(repID)
+	source := cmdInfo.Service.Rep.GetSource(repID)
 	if !cmdInfo.Service.Rep.Exists(userID) {
 		// if someone has been repped, they need to have an event or trade already...
 		// new events and trades individuals are initialized to 0 previously
@@ -33,6 +34,7 @@ func Accept(cmdInfo CommandInfo) {
 		"Accepted Reputation Request", checkThumbURL, "App ID: "+repID,
 		successColor, format(
 			createFields("Congratulations, your rep has increased!", mentionUser(userID), true),
+			createFields("From", source, false),
 		))
 	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.AppID, embed)
 }
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
+)
+
+const (
+	// defaultIncrement is the minimum raise between bids when the host
+	// doesn't set one
+	defaultIncrement = 1000
+
+	// defaultAuction is how long an auction runs when the host doesn't set
+	// a duration
+	defaultAuction = time.Hour
+
+	// minAuction and maxAuction bound the duration of an auction; trades
+	// expire after 4 hours
+	minAuction = 10 * time.Minute
+	maxAuction = 4 * time.Hour
+
+	// maxBells is the largest bell amount accepted in a bid
+	maxBells = 999999999
+)
+
+// auction holds the parsed settings of an auction trade
+type auction struct {
+	start     int
+	increment int
+	duration  time.Duration
+}
+
+// Bid places a bell bid on an auction trade
+//
+// The command usage should look like: ?bid 1234 50000
+func Bid(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) != 3 {
+		cmdInfo.bidError("Try checking your syntax.")
+		return
+	}
+	user := cmdInfo.Msg.Author
+	tradeID := cmdInfo.CmdOps[1]
+	amount, err := parseBells(cmdInfo.CmdOps[2])
+	if err != nil {
+		cmdInfo.bidError(strings.Title(err.Error()))
+		return
+	}
+	// if user doesn't exist in rep database, create a new one
+	if !cmdInfo.Service.Rep.Exists(user.ID) {
+		cmdInfo.newRep(user.ID)
+	}
+	// remember who was outbid before the new bid lands
+	prev, hadBid := cmdInfo.Service.Trade.GetTrade(tradeID).HighBid()
+	rep := cmdInfo.Service.Rep.GetRep(user.ID)
+	extended, err := cmdInfo.Service.Trade.Bid(tradeID, amount, rep, user)
+	if err != nil {
+		cmdInfo.bidError(strings.Title(err.Error()))
+		return
+	}
+	cmdInfo.updateTrade(tradeID)
+
+	t := cmdInfo.Service.Trade.GetTrade(tradeID)
+	loc := cmdInfo.Service.Profile.Location(user.ID)
+	fields := format(
+		createFields("Bidder", user.Mention(), true),
+		createFields("Bid", formatBells(amount), true),
+		createFields("Ends", formatTime(t.Ends, loc), true),
+	)
+	if extended {
+		fields = append(fields, createFields("Extended", "Bids in the last "+formatDuration(models.SnipeWindow)+
+			" extend the auction.", false))
+	}
+	embed := cmdInfo.createMsgEmbed("New High Bid!", checkThumbURL, "Trade ID: "+tradeID, successColor, fields)
+	cmdInfo.Ses.ChannelMessageSendComplex(cmdInfo.BotChID, &discordgo.MessageSend{
+		Content: t.DiscordUser.Mention() + ": Someone bid on your auction!",
+		Embed:   embed,
+	})
+
+	if hadBid && prev.User.ID != user.ID {
+		dm := cmdInfo.createMsgEmbed(
+			"You've Been Outbid", tradeThumbURL, "Trade ID: "+tradeID, tradeColor,
+			format(
+				createFields("Item", strings.Title(t.Item), true),
+				createFields("High Bid", formatBells(amount), true),
+				createFields("Bid Again", cmdInfo.Prefix+"bid "+tradeID+" "+strconv.Itoa(t.MinBid()), false),
+			))
+		cmdInfo.sendDM(prev.User.ID, dm)
+	}
+}
+
+// endAuctions announces the winners of auctions whose end passed
+func (c CommandInfo) endAuctions() {
+	for _, t := range c.Service.Trade.EndAuctions() {
+		high, ok := t.HighBid()
+		if !ok {
+			c.Service.User.RemoveTrade(t.ID, t.DiscordUser)
+			msg := c.createMsgEmbed(
+				"Auction Ended", tradeThumbURL, "Trade ID: "+t.ID, tradeColor,
+				format(
+					createFields("Item", strings.Title(t.Item), true),
+					createFields("Result", "Nobody bid on this auction.", false),
+				))
+			c.Ses.ChannelMessageSendComplex(c.BotChID, &discordgo.MessageSend{
+				Content: t.DiscordUser.Mention() + ": Your auction ended.",
+				Embed:   msg,
+			})
+			continue
+		}
+		c.finishTrade(t, high.User.ID, formatBells(high.Amount))
+	}
+}
+
+// printBids prints every bid of an auction from highest to lowest
+func printBids(cmdInfo CommandInfo, t models.TradeData) {
+	var fields []*discordgo.MessageEmbedField
+	for i := len(t.Bids) - 1; i >= 0; i-- {
+		b := t.Bids[i]
+		fields = append(fields, createFields(b.User.String(), formatBells(b.Amount), true))
+	}
+	if len(fields) == 0 {
+		fields = append(fields, createFields("No Bids", "Starting bid: "+formatBells(t.StartBid), false))
+	}
+	for i := 0; i < len(fields); i += 15 {
+		j := i + 15
+		if j > len(fields) {
+			j = len(fields)
+		}
+		msg := cmdInfo.createMsgEmbed("Total Bids", tradeThumbURL, "TradeID: "+t.ID, tradeColor, fields[i:j])
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+	}
+}
+
+// bidError prints why a bid failed with an example
+func (c CommandInfo) bidError(reason string) {
+	msg := c.createMsgEmbed(
+		"Error: Couldn't Place Bid", errThumbURL, reason, errColor,
+		format(
+			createFields("EXAMPLE", c.Prefix+"bid 1234 50000", true),
+			createFields("EXAMPLE", c.Prefix+"bid 1234 50k", true),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// parseAuction parses the auction keys of a trade command
+//
+// A trade is only an auction if it has a starting bid; nil is returned for
+// regular trades
+func parseAuction(start, increment, duration string) (*auction, error) {
+	if start == "" {
+		if increment != "" || duration != "" {
+			return nil, errors.New("auctions need a starting bid")
+		}
+		return nil, nil
+	}
+	a := &auction{
+		increment: defaultIncrement,
+		duration:  defaultAuction,
+	}
+	var err error
+	if a.start, err = parseBells(start); err != nil {
+		return nil, err
+	}
+	if increment != "" {
+		if a.increment, err = parseBells(increment); err != nil {
+			return nil, err
+		}
+	}
+	if duration != "" {
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			return nil, errors.New("duration must look like 90m or 2h")
+		}
+		if d < minAuction || d > maxAuction {
+			return nil, errors.New("auctions must run between " + formatDuration(minAuction) +
+				" and " + formatDuration(maxAuction))
+		}
+		a.duration = d
+	}
+	return a, nil
+}
+
+// parseBells parses a positive bell amount such as 50000, 50,000 or 50k
+func parseBells(str string) (int, error) {
+	str = strings.ToLower(strings.TrimSpace(str))
+	str = strings.TrimSuffix(str, "bells")
+	str = strings.ReplaceAll(str, ",", "")
+	mult := 1
+	if strings.HasSuffix(str, "k") {
+		mult = 1000
+		str = strings.TrimSuffix(str, "k")
+	}
+	n, err := strconv.Atoi(str)
+	if err != nil || n <= 0 {
+		return 0, errors.New("bells must be a positive number such as 50000 or 50k")
+	}
+	if n > maxBells/mult {
+		return 0, errors.New("that's more bells than anyone can carry")
+	}
+	return n * mult, nil
+}
+
+// formatBells prints a bell amount with thousands separators (e.g. 50,000 bells)
+func formatBells(n int) string {
+	str := strconv.Itoa(n)
+	var b strings.Builder
+	for i, r := range str {
+		if i > 0 && (len(str)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + " bells"
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBells(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		want    int
+		wantErr bool
+	}{
+		"plain":         {in: "50000", want: 50000},
+		"commas":        {in: "50,000", want: 50000},
+		"thousands":     {in: "50k", want: 50000},
+		"suffix":        {in: "50000bells", want: 50000},
+		"upper case":    {in: "12K", want: 12000},
+		"zero":          {in: "0", wantErr: true},
+		"negative":      {in: "-5", wantErr: true},
+		"not a number":  {in: "lots", wantErr: true},
+		"decimal":       {in: "1.5k", wantErr: true},
+		"too many":      {in: "1000000k", wantErr: true},
+		"largest bells": {in: "999999999", want: 999999999},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseBells(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseBells() err = %v; wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("parseBells() got = %d; want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatBells(t *testing.T) {
+	tests := map[string]struct {
+		in   int
+		want string
+	}{
+		"hundreds":  {in: 999, want: "999 bells"},
+		"thousands": {in: 1000, want: "1,000 bells"},
+		"millions":  {in: 12345678, want: "12,345,678 bells"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := formatBells(tc.in); got != tc.want {
+				t.Errorf("formatBells() got = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseAuction(t *testing.T) {
+	tests := map[string]struct {
+		start, increment, duration string
+		want                       *auction
+		wantErr                    bool
+	}{
+		"regular trade": {},
+		"defaults": {
+			start: "100k",
+			want:  &auction{start: 100000, increment: defaultIncrement, duration: defaultAuction},
+		},
+		"all set": {
+			start:     "100k",
+			increment: "5k",
+			duration:  "2h",
+			want:      &auction{start: 100000, increment: 5000, duration: 2 * time.Hour},
+		},
+		"increment without start": {
+			increment: "5k",
+			wantErr:   true,
+		},
+		"too short": {
+			start:    "100k",
+			duration: "5m",
+			wantErr:  true,
+		},
+		"too long": {
+			start:    "100k",
+			duration: "5h",
+			wantErr:  true,
+		},
+		"bad duration": {
+			start:    "100k",
+			duration: "soon",
+			wantErr:  true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseAuction(tc.start, tc.increment, tc.duration)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseAuction() err = %v; wantErr %v", err, tc.wantErr)
+			}
+			if (got == nil) != (tc.want == nil) || (got != nil && *got != *tc.want) {
+				t.Errorf("parseAuction() got = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
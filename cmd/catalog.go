@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yiping-allison/isabelle/models"
+)
+
+const (
+	// maxSuggestions is the amount of "did you mean" items shown
+	maxSuggestions = 3
+)
+
+// resolveLines replaces the items of trade lines with their canonical
+// catalog names and IDs
+//
+// If an item isn't in the catalog but looks like catalog items, it is
+// returned with suggestions and the lines should be rejected. Items nothing
+// resembles are kept as free text (ItemID 0), as are all items if the
+// catalog isn't set up.
+func (c CommandInfo) resolveLines(lines []models.TradeLine) ([]models.TradeLine, string, []string) {
+	catalog := c.Service.Catalog.All()
+	if len(catalog) == 0 {
+		return lines, "", nil
+	}
+	ret := make([]models.TradeLine, 0, len(lines))
+	for _, l := range lines {
+		if l.Item == bellsItem {
+			// bell amounts aren't catalog items
+			ret = append(ret, l)
+			continue
+		}
+		item, suggestions, ok := resolveItem(catalog, l.Item)
+		switch {
+		case ok:
+			l.Item = item.Name
+			l.ItemID = item.ID
+		case len(suggestions) > 0:
+			return nil, l.Item, suggestions
+		}
+		ret = append(ret, l)
+	}
+	return ret, "", nil
+}
+
+// suggestItems prints that an item is unknown along with the catalog items
+// the user may have meant
+func (c CommandInfo) suggestItems(title, item string, suggestions []string) {
+	var names []string
+	for _, s := range suggestions {
+		names = append(names, strings.Title(s))
+	}
+	msg := c.createMsgEmbed(
+		title, errThumbURL, "Unknown item: "+item, errColor,
+		format(
+			createFields("Did You Mean", strings.Join(names, "\n"), false),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// resolveItem looks up an item in the catalog by name or alias
+//
+// Case, punctuation and plurals are ignored. If nothing matches, up to
+// maxSuggestions catalog names that are spelled alike or contain every
+// word of the input are returned, closest first.
+func resolveItem(catalog []models.Item, input string) (models.Item, []string, bool) {
+	words := itemWords(input)
+	if len(words) == 0 {
+		return models.Item{}, nil, false
+	}
+	compact := strings.Join(words, "")
+	type suggestion struct {
+		name string
+		dist int
+	}
+	var found []suggestion
+	for _, item := range catalog {
+		best := -1
+		for _, name := range item.Names() {
+			if sameWords(words, itemWords(name)) {
+				return item, nil, true
+			}
+			dist := editDistance(compact, strings.Join(itemWords(name), ""))
+			if dist > len(compact)/5+1 && !matchItem(input, name) {
+				continue
+			}
+			if best < 0 || dist < best {
+				best = dist
+			}
+		}
+		if best >= 0 {
+			found = append(found, suggestion{name: item.Name, dist: best})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].dist != found[j].dist {
+			return found[i].dist < found[j].dist
+		}
+		return found[i].name < found[j].name
+	})
+	var ret []string
+	for i := 0; i < len(found) && i < maxSuggestions; i++ {
+		ret = append(ret, found[i].name)
+	}
+	return models.Item{}, ret, false
+}
+
+// sameWords returns true if both word lists are equal ignoring plurals
+func sameWords(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] && !plural(a[i], b[i]) && !plural(b[i], a[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yiping-allison/isabelle/models"
+)
+
+func TestResolveItem(t *testing.T) {
+	catalog := []models.Item{
+		{ID: 1, Name: "nook miles ticket", Category: models.CategoryTicket, Aliases: "nmt|miles ticket"},
+		{ID: 2, Name: "blue mountain coffee", Category: models.CategoryMaterial, Aliases: "blue mountain"},
+		{ID: 3, Name: "ironwood kitchenette", Category: models.CategoryFurniture},
+		{ID: 4, Name: "ironwood dresser", Category: models.CategoryFurniture},
+		{ID: 5, Name: "cherry-blossom petal", Category: models.CategoryMaterial},
+	}
+	tests := map[string]struct {
+		in          string
+		wantID      uint
+		suggestions []string
+	}{
+		"exact name": {
+			in:     "nook miles ticket",
+			wantID: 1,
+		},
+		"alias": {
+			in:     "NMT",
+			wantID: 1,
+		},
+		"plural": {
+			in:     "nook miles tickets",
+			wantID: 1,
+		},
+		"punctuation": {
+			in:     "cherry blossom petals",
+			wantID: 5,
+		},
+		"typo": {
+			in:          "bluemountain cofee",
+			suggestions: []string{"blue mountain coffee"},
+		},
+		"partial name": {
+			in:          "ironwood",
+			suggestions: []string{"ironwood dresser", "ironwood kitchenette"},
+		},
+		"unknown item": {
+			in: "50k bells",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			item, suggestions, ok := resolveItem(catalog, tc.in)
+			if ok != (tc.wantID != 0) || item.ID != tc.wantID {
+				t.Fatalf("resolveItem() got = %d, %v; want %d", item.ID, ok, tc.wantID)
+			}
+			if !reflect.DeepEqual(suggestions, tc.suggestions) {
+				t.Errorf("resolveItem() suggestions = %v; want %v", suggestions, tc.suggestions)
+			}
+		})
+	}
+}
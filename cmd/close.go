@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"strings"
+
+	"github.com/yiping-allison/isabelle/models"
 )
 
 // Close will attempt to parse event or trade types
@@ -42,8 +44,14 @@ func closeEvent(eventID string, cmdInfo CommandInfo) {
 		return
 	}
 
-	// store original host before removing event
-	host := cmdInfo.Service.Event.GetHost(eventID)
+	// store original event before removing it; hosts and co-hosts close
+	// their own events while anyone else closing it is a moderator
+	e := cmdInfo.Service.Event.GetEvent(eventID)
+	host := e.DiscordUser
+	reason := models.ReasonMod
+	if cmdInfo.Service.Event.IsHost(eventID, cmdInfo.Msg.Author.ID) {
+		reason = models.ReasonHost
+	}
 
 	// attempt to close the event
 	err := cmdInfo.Service.Event.Close(eventID, cmdInfo.AdminRole, cmdInfo.Msg.Author, cmdInfo.Msg.Member.Roles)
@@ -58,6 +66,9 @@ func closeEvent(eventID string, cmdInfo CommandInfo) {
 		return
 	}
 
+	// keep a record of the event
+	cmdInfo.Service.History.Archive(e, reason)
+
 	// remove all people tracking event
 	cmdInfo.Service.User.RemoveAllQueue(eventID)
 	// Remove user from tracking
@@ -71,7 +82,11 @@ func closeEvent(eventID string, cmdInfo CommandInfo) {
 			createFields("Suggestion", "If you are planning on opening another event, it is safe to do so now.", false),
 			createFields("Suggestion", "If your event was deleted by a moderator, please make sure to follow event guidelines.", false),
 		))
-	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.ListingID, embed)
+	channel := e.ChannelID
+	if channel == "" {
+		channel = cmdInfo.ListingID
+	}
+	cmdInfo.Ses.ChannelMessageSendEmbed(channel, embed)
 }
 
 // closeTrade is a helper func which closes a trade event and
@@ -88,8 +103,9 @@ func closeTrade(tradeID string, cmdInfo CommandInfo) {
 		return
 	}
 
-	// get the original creator of the trade
+	// get the original creator of the trade and where it was posted
 	host := cmdInfo.Service.Trade.GetHost(tradeID)
+	r := cmdInfo.route(cmdInfo.TradeRoutes, cmdInfo.Service.Trade.GetTrade(tradeID).Category)
 	// attempt to close the trade
 	err := cmdInfo.Service.Trade.Close(tradeID, cmdInfo.Msg.Author, cmdInfo.Msg.Member.Roles, cmdInfo.AdminRole)
 	if err != nil {
@@ -113,5 +129,5 @@ func closeTrade(tradeID string, cmdInfo CommandInfo) {
 			createFields("Suggestion", "If you are planning on opening another trade, it is safe to do so now.", false),
 			createFields("Suggestion", "If your trade was deleted by a moderator, please make sure to follow trade guidelines.", false),
 		))
-	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.ListingID, embed)
+	cmdInfo.Ses.ChannelMessageSendEmbed(r.ChannelID, embed)
 }
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"math/rand"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
@@ -29,6 +31,7 @@ const (
 	successColor int = 3764015
 	tradeColor   int = 15893760
 	appColor     int = 4617611
+	logColor     int = 7506394
 )
 
 // CommandInfo represents all metadata discord and bot needs to
@@ -49,12 +52,29 @@ type CommandInfo struct {
 	// Channel ID to post event notices
 	ListingID string
 
+	// Listing channels and role pings per event type and trade category
+	EventRoutes map[string]Route
+	TradeRoutes map[string]Route
+
 	// Channel ID to post general bot commands
+	//
+	// This is the DM channel when the command was sent by DM so replies
+	// stay private
 	BotChID string
 
+	// Channel ID of the public bot channel
+	PublicChID string
+
 	// Channel ID of rep applications
 	AppID string
 
+	// Channel ID of host and moderation logs
+	LogID string
+
+	// Reacted is true when the command was triggered by a reaction to a
+	// listing; errors are sent to the user's DMs instead of the bot channel
+	Reacted bool
+
 	// Prefix: the prefix the bot recognizes set in .config
 	Prefix string
 
@@ -94,6 +114,82 @@ func generateID(min, max int) string {
 	return strconv.Itoa(id)
 }
 
+// argRegex matches key="value" command arguments; curly quotes are accepted
+// since mobile keyboards tend to insert them
+var argRegex = regexp.MustCompile(`(\w+)\s*=\s*["“”]([^"“”]*)["“”]`)
+
+// parseArgs splits a command of key="value" pairs into a map of lower case
+// keys to their (trimmed) values
+//
+// It returns nil if the command contains anything other than key="value" pairs
+// or if a key is repeated
+func parseArgs(fullCmd string) map[string]string {
+	if strings.TrimSpace(argRegex.ReplaceAllString(fullCmd, "")) != "" {
+		return nil
+	}
+	args := make(map[string]string)
+	for _, m := range argRegex.FindAllStringSubmatch(fullCmd, -1) {
+		key := strings.ToLower(m[1])
+		if _, ok := args[key]; ok {
+			return nil
+		}
+		args[key] = strings.TrimSpace(m[2])
+	}
+	return args
+}
+
+// sendDM sends an embed to a user's direct messages
+func (c CommandInfo) sendDM(userID string, embed *discordgo.MessageEmbed) error {
+	ch, err := c.Ses.UserChannelCreate(userID)
+	if err != nil {
+		return err
+	}
+	_, err = c.Ses.ChannelMessageSendEmbed(ch.ID, embed)
+	return err
+}
+
+// replyError sends an error embed to the bot channel or to the user's DMs if
+// the command was triggered by a reaction
+func (c CommandInfo) replyError(embed *discordgo.MessageEmbed) {
+	if c.Reacted {
+		c.sendDM(c.Msg.Author.ID, embed)
+		return
+	}
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, embed)
+}
+
+// mentionedUser returns the discord user behind a ping, preferring the
+// mentions discord already sent along with the message
+func (c CommandInfo) mentionedUser(ping string) (*discordgo.User, error) {
+	id := stripPing(ping)
+	for _, u := range c.Msg.Mentions {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return c.Ses.User(id)
+}
+
+// logAction posts a record of a host or moderation action to the log channel
+// so mods can review disputes
+func (c CommandInfo) logAction(action string, fields ...*discordgo.MessageEmbedField) {
+	if c.LogID == "" {
+		return
+	}
+	by := "Isabelle"
+	if c.Msg != nil {
+		by = c.Msg.Author.String() + " (" + c.Msg.Author.ID + ")"
+	}
+	msg := c.createMsgEmbed("Log: "+action, listThumbURL, "By: "+by, logColor, fields)
+	msg.Timestamp = time.Now().Format(time.RFC3339)
+	c.Ses.ChannelMessageSendEmbed(c.LogID, msg)
+}
+
+// formatTime prints a time in the given location for users
+func formatTime(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("Mon Jan 2, 3:04 PM MST")
+}
+
 // format is a utility func which takes in a variadic parameter of discord message embed field
 // types and returns them as a slice
 func format(f ...*discordgo.MessageEmbedField) []*discordgo.MessageEmbedField { return f }
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := map[string]struct {
+		cmd  string
+		want map[string]string
+	}{
+		"simple case": {
+			cmd:  "limit=\"2\" msg=\"bonsai tree recipe\"",
+			want: map[string]string{"limit": "2", "msg": "bonsai tree recipe"},
+		},
+		"punctuation in values": {
+			cmd:  "start=\"2026-10-20 21:00\" msg=\"come over!\"",
+			want: map[string]string{"start": "2026-10-20 21:00", "msg": "come over!"},
+		},
+		"curly quotes and upper case keys": {
+			cmd:  "LIMIT=“5” msg=“hi”",
+			want: map[string]string{"limit": "5", "msg": "hi"},
+		},
+		"unquoted value": {
+			cmd:  "limit=2 msg=\"hi\"",
+			want: nil,
+		},
+		"repeated key": {
+			cmd:  "msg=\"hi\" msg=\"bye\"",
+			want: nil,
+		},
+		"empty": {
+			cmd:  "",
+			want: map[string]string{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := parseArgs(tc.cmd)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseArgs() got = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPageArg(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		wantArgs []string
+		wantPage int
+	}{
+		"no args":          {args: []string{}, wantArgs: []string{}, wantPage: 1},
+		"only page":        {args: []string{"2"}, wantArgs: []string{}, wantPage: 2},
+		"keyword and page": {args: []string{"gold", "nugget", "3"}, wantArgs: []string{"gold", "nugget"}, wantPage: 3},
+		"keyword only":     {args: []string{"diy"}, wantArgs: []string{"diy"}, wantPage: 1},
+		"invalid page":     {args: []string{"diy", "0"}, wantArgs: []string{"diy", "0"}, wantPage: 1},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotArgs, gotPage := pageArg(tc.args)
+			if !reflect.DeepEqual(gotArgs, tc.wantArgs) || gotPage != tc.wantPage {
+				t.Errorf("pageArg() got = %v, %d; want %v, %d", gotArgs, gotPage, tc.wantArgs, tc.wantPage)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"strings"
+)
+
+// CoHost lets event hosts add and remove co-hosts who can advance the queue,
+// close the event and receive join notifications
+//
+// The command usage should look like: ?cohost add 1234 @user
+func CoHost(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) != 4 {
+		cmdInfo.coHostSyntaxError()
+		return
+	}
+	action := strings.ToLower(cmdInfo.CmdOps[1])
+	eventID := cmdInfo.CmdOps[2]
+	if action != "add" && action != "remove" {
+		cmdInfo.coHostSyntaxError()
+		return
+	}
+	if !cmdInfo.Service.Event.EventExists(eventID) {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Event Not Found", errThumbURL, "Event ID: "+eventID, errColor,
+			format(
+				createFields("Suggestion", "Try checking if you supplied a valid Event ID.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	host := cmdInfo.Service.Event.GetHost(eventID)
+	if host.ID != cmdInfo.Msg.Author.ID && !isAdmin(cmdInfo.Msg.Member.Roles, cmdInfo.AdminRole) {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: You do not have permission to change this event", errThumbURL, "Event ID: "+eventID, errColor,
+			format(
+				createFields("Suggestion", "Only the original host can add or remove co-hosts.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	user, err := cmdInfo.mentionedUser(cmdInfo.CmdOps[3])
+	if err == nil {
+		if action == "add" {
+			err = cmdInfo.Service.Event.AddCoHost(eventID, user)
+		} else {
+			err = cmdInfo.Service.Event.RemoveCoHost(eventID, user.ID)
+		}
+	}
+	if err != nil {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Update Co-Hosts", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"cohost add 1234 @user", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	cmdInfo.updateEvent(eventID)
+	title := "Co-Host Added"
+	if action == "remove" {
+		title = "Co-Host Removed"
+	}
+	msg := cmdInfo.createMsgEmbed(
+		title, checkThumbURL, "Queue ID: "+eventID, successColor,
+		format(
+			createFields("Hosted By", cmdInfo.hostMentions(eventID), true),
+		))
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+}
+
+// coHostSyntaxError prints all co-host command examples
+func (c CommandInfo) coHostSyntaxError() {
+	msg := c.createMsgEmbed(
+		"Error: Wrong Arguments", errThumbURL, "Try checking your syntax.", errColor,
+		format(
+			createFields("EXAMPLE", c.Prefix+"cohost add 1234 @user", true),
+			createFields("EXAMPLE", c.Prefix+"cohost remove 1234 @user", true),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
+)
+
+// Confirm lets a called user confirm they're on their way to the host
+//
+// Users who don't confirm within the event's confirm window are skipped and
+// recorded as a no-show
+//
+// The command usage should look like: ?confirm 1234
+func Confirm(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) != 2 {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Wrong Arguments", errThumbURL, "Try checking your syntax.", errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"confirm 1234", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	user := cmdInfo.Msg.Author
+	eventID := cmdInfo.CmdOps[1]
+	if err := cmdInfo.Service.Event.Confirm(eventID, user.ID); err != nil {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Confirm", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("Queue ID", eventID, true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	e := cmdInfo.Service.Event.GetEvent(eventID)
+	cmdInfo.Service.Attendance.Record(&models.Attendance{
+		UserID:  user.ID,
+		EventID: eventID,
+		HostID:  e.DiscordUser.ID,
+		Showed:  true,
+	})
+	msg := cmdInfo.createMsgEmbed(
+		"Visit Confirmed!", checkThumbURL, "Queue ID: "+eventID, successColor,
+		format(
+			createFields("User", user.Mention(), true),
+			createFields("Event", e.Name, true),
+		))
+	cmdInfo.Ses.ChannelMessageSendComplex(cmdInfo.BotChID, &discordgo.MessageSend{
+		Content: cmdInfo.hostMentions(eventID) + ": " + user.Mention() + " is on their way!",
+		Embed:   msg,
+	})
+}
+
+// skipNoShows records called users who didn't confirm in time as no-shows and
+// calls the next user in their queue
+func (c CommandInfo) skipNoShows() {
+	for _, ns := range c.Service.Event.NoShows() {
+		c.Service.Attendance.Record(&models.Attendance{
+			UserID:  ns.User.ID,
+			EventID: ns.EventID,
+			HostID:  ns.HostID,
+			Showed:  false,
+		})
+		msg := c.createMsgEmbed(
+			"Skipped: No Confirmation", errThumbURL, "Queue ID: "+ns.EventID, errColor,
+			format(
+				createFields("Event", ns.Name, true),
+				createFields("Note", "You didn't confirm in time so the host moved on. No-shows lower your reliability score.", false),
+			))
+		c.sendDM(ns.User.ID, msg)
+		c.logAction("No-Show",
+			createFields("Event ID", ns.EventID, true),
+			createFields("User", mentionUser(ns.User.ID), true),
+		)
+		e := c.Service.Event.GetEvent(ns.EventID)
+		if e.DiscordUser == nil {
+			// event was closed in the meantime
+			continue
+		}
+		c.Ses.ChannelMessageSendEmbed(c.BotChID, c.createMsgEmbed(
+			"No-Show Skipped", errThumbURL, "Queue ID: "+ns.EventID, errColor,
+			format(
+				createFields("User", ns.User.Mention(), true),
+				createFields("Queue Left", strconv.Itoa(len(e.Queue)), true),
+			)))
+		if len(e.Queue) > 0 {
+			c.callNext(ns.EventID)
+		}
+	}
+}
+
+// reliabilityText prints a user's attendance score
+func (c CommandInfo) reliabilityText(userID string) string {
+	score, n := c.Service.Attendance.Reliability(userID)
+	if n == 0 {
+		return "No history yet"
+	}
+	return strconv.Itoa(score) + "% of last " + strconv.Itoa(n) + " calls"
+}
+
+// reliabilityWeight scales a lottery weight by attendance so repeat no-shows
+// are less likely to be drawn early; it never drops below a quarter
+func reliabilityWeight(score int) float64 {
+	if score < 25 {
+		score = 25
+	}
+	if score > 100 {
+		score = 100
+	}
+	return float64(score) / 100
+}
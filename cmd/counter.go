@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
+)
+
+// Counter lets a trade host answer an offer with a counter-offer; the
+// offerer can accept it with ?offer accept
+//
+// The command usage should look like: ?counter 1234 @user 3 nmt instead
+func Counter(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) < 4 {
+		cmdInfo.offerReplyError("Error: Couldn't Counter Offer", "Try checking your syntax.")
+		return
+	}
+	t, userID, ok := cmdInfo.hostTrade("Error: Couldn't Counter Offer")
+	if !ok {
+		return
+	}
+	counter := strings.Title(strings.Join(cmdInfo.CmdOps[3:], " "))
+	o, err := cmdInfo.Service.Trade.UpdateOffer(t.ID, userID, models.OfferCountered, counter)
+	if err != nil {
+		cmdInfo.offerReplyError("Error: Couldn't Counter Offer", strings.Title(err.Error()))
+		return
+	}
+	dm := cmdInfo.createMsgEmbed(
+		"Counter-Offer Received", tradeThumbURL, "Trade ID: "+t.ID, tradeColor,
+		format(
+			createFields("Item", strings.Title(t.Item), true),
+			createFields("Host", t.DiscordUser.Mention(), true),
+			createFields("Your Offer", o.Offer, false),
+			createFields("Counter-Offer", counter, false),
+			createFields("Accept", cmdInfo.Prefix+"offer accept "+t.ID, true),
+			createFields("Withdraw", cmdInfo.Prefix+"offer withdraw "+t.ID, true),
+		))
+	cmdInfo.sendDM(userID, dm)
+	msg := cmdInfo.createMsgEmbed(
+		"Counter-Offer Sent", checkThumbURL, "Trade ID: "+t.ID, successColor,
+		format(
+			createFields("Offerer", o.User.Mention(), true),
+			createFields("Counter-Offer", counter, false),
+		))
+	cmdInfo.hostReply(t, msg)
+}
+
+// Decline lets a trade host turn down an offer
+//
+// The command usage should look like: ?decline 1234 @user [reason]
+func Decline(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) < 3 {
+		cmdInfo.offerReplyError("Error: Couldn't Decline Offer", "Try checking your syntax.")
+		return
+	}
+	t, userID, ok := cmdInfo.hostTrade("Error: Couldn't Decline Offer")
+	if !ok {
+		return
+	}
+	o, err := cmdInfo.Service.Trade.UpdateOffer(t.ID, userID, models.OfferDeclined, "")
+	if err != nil {
+		cmdInfo.offerReplyError("Error: Couldn't Decline Offer", strings.Title(err.Error()))
+		return
+	}
+	cmdInfo.Service.User.RemoveOffer(t.ID, o.User)
+	fields := format(
+		createFields("Item", strings.Title(t.Item), true),
+		createFields("Host", t.DiscordUser.Mention(), true),
+		createFields("Your Offer", o.Offer, false),
+	)
+	if len(cmdInfo.CmdOps) > 3 {
+		fields = append(fields, createFields("Reason", strings.Join(cmdInfo.CmdOps[3:], " "), false))
+	}
+	fields = append(fields, createFields("Suggestion", "You can make a new offer with "+cmdInfo.Prefix+"offer "+t.ID, false))
+	dm := cmdInfo.createMsgEmbed("Offer Declined", tradeThumbURL, "Trade ID: "+t.ID, tradeColor, fields)
+	cmdInfo.sendDM(userID, dm)
+	msg := cmdInfo.createMsgEmbed(
+		"Offer Declined", checkThumbURL, "Trade ID: "+t.ID, successColor,
+		format(
+			createFields("Offerer", o.User.Mention(), true),
+		))
+	cmdInfo.hostReply(t, msg)
+}
+
+// acceptCounter lets an offerer take the host's counter-offer which
+// completes the trade
+//
+// The command usage should look like: ?offer accept 1234
+func acceptCounter(cmdInfo CommandInfo, tradeID string) {
+	user := cmdInfo.Msg.Author
+	t := cmdInfo.Service.Trade.GetTrade(tradeID)
+	o, ok := cmdInfo.Service.Trade.FindOffer(tradeID, user.ID)
+	if !ok || o.State != models.OfferCountered {
+		cmdInfo.offerReplyError("Error: Couldn't Accept Counter-Offer", "The host hasn't countered your offer.")
+		return
+	}
+	if _, err := cmdInfo.Service.Trade.UpdateOffer(tradeID, user.ID, models.OfferAccepted, ""); err != nil {
+		cmdInfo.offerReplyError("Error: Couldn't Accept Counter-Offer", strings.Title(err.Error()))
+		return
+	}
+	if err := cmdInfo.Service.Trade.Close(tradeID, t.DiscordUser, nil, cmdInfo.AdminRole); err != nil {
+		return
+	}
+	fields := format(createFields("Offerer", user.Mention(), true))
+	if !t.Sealed {
+		fields = append(fields, createFields("Counter-Offer", o.Counter, false))
+	}
+	msg := cmdInfo.createMsgEmbed("Counter-Offer Accepted", checkThumbURL, "Trade ID: "+tradeID, successColor, fields)
+	cmdInfo.Ses.ChannelMessageSendComplex(cmdInfo.PublicChID, &discordgo.MessageSend{
+		Content: t.DiscordUser.Mention() + ": Your counter-offer was accepted!",
+		Embed:   msg,
+	})
+	cmdInfo.finishTrade(t, user.ID, o.Counter)
+}
+
+// hostTrade makes sure the author hosts the trade in CmdOps[1] and returns
+// it along with the ID of the offerer mentioned in CmdOps[2]
+func (c CommandInfo) hostTrade(title string) (models.TradeData, string, bool) {
+	t := c.Service.Trade.GetTrade(c.CmdOps[1])
+	if t.DiscordUser == nil || t.DiscordUser.ID != c.Msg.Author.ID {
+		c.offerReplyError(title, "Only the host of an active trade can do this.")
+		return models.TradeData{}, "", false
+	}
+	return t, stripPing(c.CmdOps[2]), true
+}
+
+// hostReply confirms a host's answer to an offer; answers to sealed trades
+// are only sent by DM and the command is hidden if it was posted publicly
+func (c CommandInfo) hostReply(t models.TradeData, msg *discordgo.MessageEmbed) {
+	if !t.Sealed {
+		c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+		return
+	}
+	if c.Msg.GuildID != "" {
+		c.Ses.ChannelMessageDelete(c.Msg.ChannelID, c.Msg.ID)
+	}
+	c.sendDM(c.Msg.Author.ID, msg)
+}
+
+// offerReplyError prints why a host or offerer couldn't respond to an offer
+func (c CommandInfo) offerReplyError(title, reason string) {
+	msg := c.createMsgEmbed(
+		title, errThumbURL, reason, errColor,
+		format(
+			createFields("EXAMPLE", c.Prefix+"counter 1234 @user 3 nmt instead", true),
+			createFields("EXAMPLE", c.Prefix+"decline 1234 @user", true),
+			createFields("EXAMPLE", c.Prefix+"offer accept 1234", true),
+			createFields("EXAMPLE", c.Prefix+"offer withdraw 1234", true),
+			createFields("EXAMPLE", c.Prefix+"offer edit 1234 3x nmt", true),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// offerStatus prints an offer with its state, the host's counter-offer and
+// the earlier versions of an edited offer
+func offerStatus(o models.TradeOfferer) string {
+	str := o.Offer + "\nState: " + strings.Title(o.State)
+	if o.State == models.OfferCountered {
+		str += "\nCounter: " + o.Counter
+	}
+	if len(o.History) > 0 {
+		var earlier []string
+		for _, r := range o.History {
+			earlier = append(earlier, r.Offer)
+		}
+		str += "\nEdited From: " + strings.Join(earlier, " → ")
+	}
+	return str
+}
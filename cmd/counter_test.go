@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/yiping-allison/isabelle/models"
+)
+
+func TestOfferStatus(t *testing.T) {
+	tests := map[string]struct {
+		in   models.TradeOfferer
+		want string
+	}{
+		"pending": {
+			in:   models.TradeOfferer{Offer: "2x Nmt", State: models.OfferPending},
+			want: "2x Nmt\nState: Pending",
+		},
+		"countered": {
+			in:   models.TradeOfferer{Offer: "2x Nmt", State: models.OfferCountered, Counter: "3 Nmt"},
+			want: "2x Nmt\nState: Countered\nCounter: 3 Nmt",
+		},
+		"edited": {
+			in: models.TradeOfferer{
+				Offer: "3x Nmt",
+				State: models.OfferPending,
+				History: []models.OfferRevision{
+					{Offer: "1x Nmt"},
+					{Offer: "2x Nmt"},
+				},
+			},
+			want: "3x Nmt\nState: Pending\nEdited From: 1x Nmt → 2x Nmt",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := offerStatus(tc.in); got != tc.want {
+				t.Errorf("offerStatus() got = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
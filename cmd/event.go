@@ -1,11 +1,10 @@
 package cmd
 
 import (
-	"bytes"
-	"fmt"
+	"errors"
 	"strconv"
 	"strings"
-	"unicode"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/yiping-allison/isabelle/models"
@@ -23,6 +22,47 @@ type newEvent struct {
 
 	// Custom message set by event hosts - must be within character ranges
 	Msg string
+
+	// Optional time the queue opens; empty means right away
+	Start string
+
+	// Optional size of the waitlist behind a full queue
+	Waitlist string
+
+	// Optional lottery settings (mode, entry window and rep weighting)
+	Mode     string
+	Window   string
+	Weighted string
+
+	// Optional reputation users need to join
+	MinRep string
+
+	// Optional time called users have to confirm and attendance score
+	// users need to join
+	Confirm        string
+	MinReliability string
+}
+
+const (
+	// maxSchedule is how far ahead an event can be scheduled
+	maxSchedule = 7 * 24 * time.Hour
+)
+
+// eventType holds the display name and image of an event keyword
+type eventType struct {
+	name string
+	img  string
+}
+
+// eventTypes maps every event keyword to its display name and image
+var eventTypes = map[string]eventType{
+	"celeste":  {"Celeste", celesteURL},
+	"daisymae": {"Daisy Mae", daisymaeURL},
+	"saharah":  {"Saharah", saharahURL},
+	"diy":      {"DIY", diyURL},
+	"meteor":   {"Meteor Shower", meteorURL},
+	"turnip":   {"Turnip - High Sell Price", daisymaeURL},
+	"kicks":    {"Kicks", kicksURL},
 }
 
 const (
@@ -36,6 +76,8 @@ const (
 
 // Event will parse through event commands and display embed with
 // role ping
+//
+// Listings are posted to the channel routed to the event type in .config
 func Event(cmdInfo CommandInfo) {
 	if len(cmdInfo.CmdOps) == 1 {
 		// No arguments supplied - error
@@ -60,129 +102,449 @@ func Event(cmdInfo CommandInfo) {
 			cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
 			return
 		}
-		queue := cmdInfo.Service.Event.GetQueue(cmdInfo.CmdOps[1])
-		fields := queueToFields(queue)
+		e := cmdInfo.Service.Event.GetEvent(cmdInfo.CmdOps[1])
+		fields := queueToFields(&e.Queue)
+		for i, u := range e.Waitlist {
+			fields = append(fields, createFields("Waitlist Number: "+strconv.Itoa(i+1), u.DiscordUser.String(), true))
+		}
+		if e.Lottery && !e.Drawn {
+			fields = append(fields, createFields("Lottery Entries", strconv.Itoa(len(e.Entries)), true))
+		}
+		if !e.Open {
+			loc := cmdInfo.Service.Profile.Location(e.DiscordUser.ID)
+			fields = append(fields,
+				createFields("Upcoming", "Opens "+formatTime(e.Start, loc), true),
+				createFields("Subscribed", strconv.Itoa(len(e.Subscribers)), true),
+			)
+		}
 		msg := cmdInfo.createMsgEmbed(
 			"Current Queue", queueThumbURL, "Queue ID: "+cmdInfo.CmdOps[1], eventColor, fields)
 		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
 		return
 	}
 
+	if strings.ToLower(cmdInfo.CmdOps[1]) == "limit" {
+		setLimit(cmdInfo)
+		return
+	}
+
+	switch strings.ToLower(cmdInfo.CmdOps[1]) {
+	case "save", "run", "repeat", "templates", "delete":
+		eventTemplate(cmdInfo)
+		return
+	}
+
 	eventName := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(cmdInfo.CmdOps[1])), " ", "")
-	cmd := strings.Join(cmdInfo.CmdOps[2:], " ")
-	var event *newEvent
-	switch eventName {
-	case "celeste":
-		event = parseCmd(cmd, "Celeste", celesteURL)
-	case "daisymae":
-		event = parseCmd(cmd, "Daisy Mae", daisymaeURL)
-	case "saharah":
-		event = parseCmd(cmd, "Saharah", saharahURL)
-	case "diy":
-		event = parseCmd(cmd, "DIY", diyURL)
-	case "meteor":
-		event = parseCmd(cmd, "Meteor Shower", meteorURL)
-	case "turnip":
-		event = parseCmd(cmd, "Turnip - High Sell Price", daisymaeURL)
-	case "kicks":
-		event = parseCmd(cmd, "Kicks", kicksURL)
-	default:
+	eType, ok := eventTypes[eventName]
+	if !ok {
 		return
 	}
+	event := parseCmd(strings.Join(cmdInfo.CmdOps[2:], " "), eType.name, eType.img)
 
 	if event == nil {
 		// Couldn't create an event - error
-		fmt.Println(event)
-		msg := cmdInfo.createMsgEmbed(
-			"Error: Couldn't Create Event", errThumbURL, "Try checking your command's syntax.", errColor,
-			format(
+		cmdInfo.printEventError("Error: Couldn't Create Event", eventError{
+			msg: "Try checking your command's syntax.",
+			fields: format(
 				createFields("EXAMPLE", cmdInfo.Prefix+"event diy limit=\"5\" msg=\"ironwood bed\"", false),
-			))
-		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+			),
+		})
 		return
 	}
 
+	data, err := cmdInfo.buildEvent(eventName, event, cmdInfo.Msg.Author)
+	if err == nil {
+		_, err = cmdInfo.listEvent(data)
+	}
+	if err != nil {
+		cmdInfo.printEventError("Error: Couldn't Create Event", err)
+		return
+	}
+	cmdInfo.Ses.ChannelMessageSend(cmdInfo.BotChID, "Listing Posted!")
+}
+
+// eventError describes why an event couldn't be created along with
+// examples of the right syntax
+type eventError struct {
+	msg    string
+	fields []*discordgo.MessageEmbedField
+}
+
+func (e eventError) Error() string {
+	return e.msg
+}
+
+// printEventError prints why an event couldn't be created or saved
+func (c CommandInfo) printEventError(title string, err error) {
+	var fields []*discordgo.MessageEmbedField
+	if e, ok := err.(eventError); ok {
+		fields = e.fields
+	}
+	msg := c.createMsgEmbed(title, errThumbURL, err.Error(), errColor, fields)
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// buildEvent validates the options of a new event hosted by host
+//
+// Errors are of type eventError so they can be printed with examples
+func (c CommandInfo) buildEvent(eventName string, event *newEvent, host *discordgo.User) (*models.EventData, error) {
 	limit, err := strconv.Atoi(event.Limit)
 	if err != nil || limit > 20 || limit < 1 {
 		// Error - couldn't convert limit value into a number (limit MUST be a number)
 		// Limit must be within bounds 1 - 20
-		msg := cmdInfo.createMsgEmbed(
-			"Error: Couldn't Create Event", errThumbURL, "Your limit must be a valid number", errColor,
-			format(
-				createFields("EXAMPLE", cmdInfo.Prefix+"event diy limit=\"5\" msg=\"ironwood bed\"", false),
-			))
-		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
-		return
+		return nil, eventError{
+			msg: "Your limit must be a valid number",
+			fields: format(
+				createFields("EXAMPLE", c.Prefix+"event diy limit=\"5\" msg=\"ironwood bed\"", false),
+			),
+		}
+	}
+
+	waitlist := 0
+	if event.Waitlist != "" {
+		waitlist, err = strconv.Atoi(event.Waitlist)
+		if err != nil || waitlist > 20 || waitlist < 0 {
+			// Waitlist must be within bounds 0 - 20
+			return nil, eventError{
+				msg: "Your waitlist must be a number between 0 and 20",
+				fields: format(
+					createFields("EXAMPLE", c.Prefix+"event diy limit=\"5\" waitlist=\"5\" msg=\"ironwood bed\"", false),
+				),
+			}
+		}
+	}
+
+	minRep, err := parseMinRep(event.MinRep)
+	if err != nil {
+		return nil, eventError{
+			msg: strings.Title(err.Error()),
+			fields: format(
+				createFields("EXAMPLE", c.Prefix+"event celeste limit=\"5\" minrep=\"3\" msg=\"wishing on stars\"", false),
+			),
+		}
+	}
+	// server wide minimums set by mods always apply
+	if serverMin := c.Service.MinRep.Get(eventName); serverMin > minRep {
+		minRep = serverMin
+	}
+
+	confirm, err := parseConfirm(event.Confirm)
+	var minReliability int
+	if err == nil {
+		minReliability, err = parseMinReliability(event.MinReliability)
+	}
+	if err != nil {
+		return nil, eventError{
+			msg: strings.Title(err.Error()),
+			fields: format(
+				createFields("EXAMPLE", c.Prefix+"event celeste limit=\"5\" confirm=\"3m\" minreliability=\"80\" msg=\"wishing on stars\"", false),
+			),
+		}
+	}
+
+	lottery, window, weighted, err := parseLottery(event)
+	if err != nil {
+		return nil, eventError{
+			msg: strings.Title(err.Error()),
+			fields: format(
+				createFields("EXAMPLE", c.Prefix+"event turnip limit=\"20\" mode=\"lottery\" window=\"15m\" weighted=\"yes\" msg=\"600 bells\"", false),
+			),
+		}
 	}
 
 	if !validMsg(event.Msg, eventName) {
 		// Error - message must be within 50 or 100 characters
-		msg := cmdInfo.createMsgEmbed(
-			"Error: Couldn't Create Event", errThumbURL, "Your message must be within valid length", errColor,
-			format(
-				createFields("EXAMPLE", cmdInfo.Prefix+"event diy limit=\"5\" msg=\"ironwood bed\"", false),
-			))
-		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
-		return
+		return nil, eventError{
+			msg: "Your message must be within valid length",
+			fields: format(
+				createFields("EXAMPLE", c.Prefix+"event diy limit=\"5\" msg=\"ironwood bed\"", false),
+			),
+		}
 	}
 
-	if cmdInfo.Service.User.LimitEvent(cmdInfo.Msg.Author) {
+	var start time.Time
+	if event.Start != "" {
+		loc := c.Service.Profile.Location(host.ID)
+		start, err = parseStart(event.Start, loc, time.Now())
+		if err != nil {
+			// Error - start time couldn't be read or is out of range
+			return nil, eventError{
+				msg: strings.Title(err.Error()),
+				fields: format(
+					createFields("EXAMPLE", c.Prefix+"event meteor start=\"2026-10-20 21:00\" limit=\"5\" msg=\"shooting stars\"", false),
+					createFields("EXAMPLE", c.Prefix+"event meteor start=\"in 3h\" limit=\"5\" msg=\"shooting stars\"", false),
+					createFields("Timezone", "Times are read in your timezone; set it with "+c.Prefix+"timezone", false),
+				),
+			}
+		}
+	}
+
+	return &models.EventData{
+		Type:           eventName,
+		Name:           event.Name,
+		Img:            event.Img,
+		Msg:            event.Msg,
+		DiscordUser:    host,
+		Limit:          limit,
+		WaitlistLimit:  waitlist,
+		Start:          start,
+		Lottery:        lottery,
+		Window:         window,
+		Weighted:       weighted,
+		MinRep:         minRep,
+		ConfirmWindow:  confirm,
+		MinReliability: minReliability,
+	}, nil
+}
+
+// listEvent adds a validated event to tracking and posts its listing
+//
+// It returns the new event ID
+func (c CommandInfo) listEvent(data *models.EventData) (string, error) {
+	user := data.DiscordUser
+	if c.Service.User.LimitEvent(user) {
 		// Error - Cannot create anymore events
-		msg := cmdInfo.createMsgEmbed(
-			"Error: Couldn't Create Event", errThumbURL, "You already have the max amount of events.", errColor,
-			format(
+		return "", eventError{
+			msg: "You already have the max amount of events.",
+			fields: format(
 				createFields("Suggestion", "Either end one of your events or wait until your events are finished before creating another.", false),
-			))
-		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
-		return
+			),
+		}
 	}
 
-	user := cmdInfo.Msg.Author
 	// if user doesn't exist in rep database, create a new one
-	if !cmdInfo.Service.Rep.Exists(user.ID) {
-		cmdInfo.newRep(user.ID)
+	if !c.Service.Rep.Exists(user.ID) {
+		c.newRep(user.ID)
 	}
 
 	// if the user doesn't currently exist in tracking, create a new one
-	if !cmdInfo.Service.User.UserExists(user) {
-		cmdInfo.Service.User.AddUser(user)
+	if !c.Service.User.UserExists(user) {
+		c.Service.User.AddUser(user)
 	}
 
 	// generate a random id with at least 4 digits
 	id := generateID(1000, 9999)
 
-	if cmdInfo.Service.Event.EventExists(id) {
+	if c.Service.Event.EventExists(id) {
 		// There's an event with the same ID
+		return "", eventError{
+			msg: "There is already an event with this ID; Please try again.",
+			fields: format(
+				createFields("EXAMPLE", c.Prefix+"event diy limit=\"5\" msg=\"ironwood bed\"", false),
+			),
+		}
+	}
+
+	// Add the event to tracking
+	c.Service.Event.AddEvent(id, data)
+	// record expiration time
+	expire := c.Service.Event.GetExpiration(id)
+	c.Service.User.AddEvent(user, id, expire)
+
+	c.postEvent(id)
+	c.notifySubscribers(c.Service.Subscription.Events(data.Type), user.ID, c.eventEmbed(id))
+	return id, nil
+}
+
+// setLimit lets hosts change the queue limit of their event; raising the
+// limit promotes users from the waitlist
+//
+// The command usage should look like: ?event limit 1234 10
+func setLimit(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) != 4 {
 		msg := cmdInfo.createMsgEmbed(
-			"Error: Couldn't Create Event", errThumbURL, "There is already an event with this ID; Please try again.", errColor,
+			"Error: Wrong Arguments", errThumbURL, "Try checking your syntax.", errColor,
 			format(
-				createFields("EXAMPLE", cmdInfo.Prefix+"event diy limit=\"5\" msg=\"ironwood bed\"", false),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event limit 1234 10", false),
 			))
 		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
 		return
 	}
-
-	// Add the event to tracking
-	cmdInfo.Service.Event.AddEvent(user, id, limit)
-	// record expiration time
-	expire := cmdInfo.Service.Event.GetExpiration(id)
-	cmdInfo.Service.User.AddEvent(user, id, expire)
-	// retrieve reputation
-	rep := cmdInfo.Service.Rep.GetRep(user.ID)
-
+	eventID := cmdInfo.CmdOps[2]
+	if !cmdInfo.Service.Event.EventExists(eventID) {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Event Not Found", errThumbURL, "Event ID: "+eventID, errColor,
+			format(
+				createFields("Suggestion", "Try checking if you supplied a valid Event ID.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	if !cmdInfo.Service.Event.IsHost(eventID, cmdInfo.Msg.Author.ID) && !isAdmin(cmdInfo.Msg.Member.Roles, cmdInfo.AdminRole) {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: You do not have permission to change this event", errThumbURL, "Event ID: "+eventID, errColor,
+			format(
+				createFields("Suggestion", "Only the host and co-hosts can change the queue limit.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	limit, err := strconv.Atoi(cmdInfo.CmdOps[3])
+	if err != nil || limit > 20 || limit < len(*cmdInfo.Service.Event.GetQueue(eventID)) || limit < 1 {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Change Limit", errThumbURL, "Your limit must be a valid number", errColor,
+			format(
+				createFields("Suggestion", "The limit must be between 1 and 20 and can't be less than the current queue.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	promoted := cmdInfo.Service.Event.SetLimit(eventID, limit)
+	cmdInfo.updateEvent(eventID)
 	msg := cmdInfo.createMsgEmbed(
-		"Event: "+event.Name,
-		event.Img,
-		"Queue ID: "+id,
-		eventColor,
+		"Queue Limit Changed", checkThumbURL, "Queue ID: "+eventID, successColor,
 		format(
-			createFields("Hosted By", user.Mention(), true),
-			createFields("Reputation", strconv.Itoa(rep), true),
-			createFields("Limit", event.Limit, false),
-			createFields("Message", event.Msg, false),
+			createFields("Limit", strconv.Itoa(limit), true),
+			createFields("Promoted From Waitlist", strconv.Itoa(len(promoted)), true),
 		))
-	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.ListingID, msg)
-	cmdInfo.Ses.ChannelMessageSend(cmdInfo.BotChID, "Listing Posted!")
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+	cmdInfo.notifyPromoted(eventID, promoted)
+	cmdInfo.refreshPositions(eventID)
+}
+
+// postEvent sends the listing of an event to the listing channel of its type,
+// pings the type's role and remembers where it was posted so it can be
+// updated later
+func (c CommandInfo) postEvent(eventID string) {
+	e := c.Service.Event.GetEvent(eventID)
+	r := c.route(c.EventRoutes, e.Type)
+	m, err := c.Ses.ChannelMessageSendComplex(r.ChannelID, &discordgo.MessageSend{
+		Content: r.rolePing(),
+		Embed:   c.eventEmbed(eventID),
+	})
+	if err != nil {
+		return
+	}
+	c.Service.Event.SetListing(eventID, m.ChannelID, m.ID)
+	c.Ses.MessageReactionAdd(m.ChannelID, m.ID, JoinEmoji)
+}
+
+// updateEvent edits an event's listing message to match its current state
+func (c CommandInfo) updateEvent(eventID string) {
+	e := c.Service.Event.GetEvent(eventID)
+	if e.MessageID == "" {
+		return
+	}
+	c.Ses.ChannelMessageEditEmbed(e.ChannelID, e.MessageID, c.eventEmbed(eventID))
+}
+
+// eventEmbed builds the listing embed of an event from its current state
+func (c CommandInfo) eventEmbed(eventID string) *discordgo.MessageEmbed {
+	e := c.Service.Event.GetEvent(eventID)
+	rep := c.Service.Rep.GetRep(e.DiscordUser.ID)
+	title := "Event: " + e.Name
+	fields := format(
+		createFields("Hosted By", c.hostMentions(eventID), true),
+		createFields("Reputation", strconv.Itoa(rep), true),
+		createFields("Limit", strconv.Itoa(e.Limit), true),
+	)
+	if e.WaitlistLimit > 0 {
+		fields = append(fields, createFields("Waitlist", strconv.Itoa(e.WaitlistLimit), true))
+	}
+	if e.MinRep > 0 {
+		fields = append(fields, createFields("Minimum Rep", strconv.Itoa(e.MinRep), true))
+	}
+	if e.MinReliability > 0 {
+		fields = append(fields, createFields("Minimum Reliability", strconv.Itoa(e.MinReliability)+"%", true))
+	}
+	if e.ConfirmWindow > 0 {
+		fields = append(fields, createFields("Confirm Within", formatDuration(e.ConfirmWindow), true))
+	}
+	fields = append(fields, createFields("Message", e.Msg, false))
+	if e.Locked {
+		fields = append(fields, createFields("Status", "Locked by host", false))
+	}
+	if e.Lottery {
+		loc := c.Service.Profile.Location(e.DiscordUser.ID)
+		mode := "Entries close " + formatTime(e.Start.Add(e.Window), loc) + ", then the queue order is drawn at random"
+		if e.Weighted {
+			mode += " (weighted by reputation)"
+		}
+		fields = append(fields, createFields("Lottery", mode, false))
+	}
+	if e.Open {
+		fields = append(fields, createFields("Join", c.Prefix+"queue "+eventID+" or react "+JoinEmoji, false))
+	} else {
+		title += " (Upcoming)"
+		loc := c.Service.Profile.Location(e.DiscordUser.ID)
+		fields = append(fields,
+			createFields("Queue Opens", formatTime(e.Start, loc), true),
+			createFields("Get Reminded", c.Prefix+"queue "+eventID+" or react "+JoinEmoji, true),
+		)
+	}
+	return c.createMsgEmbed(title, e.Img, "Queue ID: "+eventID, eventColor, fields)
+}
+
+// parseMinRep reads an optional minimum reputation argument
+func parseMinRep(minRep string) (int, error) {
+	if minRep == "" {
+		return 0, nil
+	}
+	min, err := strconv.Atoi(minRep)
+	if err != nil || min < 0 || min > 1000 {
+		return 0, errors.New("minrep must be a number between 0 and 1000")
+	}
+	return min, nil
+}
+
+// parseConfirm reads how long called users have to confirm they're coming;
+// confirmations are off unless the host sets a window
+func parseConfirm(confirm string) (time.Duration, error) {
+	switch strings.ToLower(confirm) {
+	case "", "off":
+		return 0, nil
+	}
+	d, err := time.ParseDuration(strings.ReplaceAll(strings.ToLower(confirm), " ", ""))
+	if err != nil || d < time.Minute || d > 30*time.Minute {
+		return 0, errors.New("confirm must be off or between 1m and 30m")
+	}
+	return d, nil
+}
+
+// parseMinReliability reads an optional minimum attendance score
+func parseMinReliability(min string) (int, error) {
+	if min == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(min, "%"))
+	if err != nil || n < 0 || n > 100 {
+		return 0, errors.New("minreliability must be a number between 0 and 100")
+	}
+	return n, nil
+}
+
+// parseStart reads the start time of a scheduled event in the host's location
+//
+// Accepted formats are a full date and time (2006-01-02 15:04), a time of day (21:00)
+// which means its next occurrence, or a relative time (in 3h, in 1h30m)
+func parseStart(start string, loc *time.Location, now time.Time) (time.Time, error) {
+	start = strings.ToLower(strings.TrimSpace(start))
+	var t time.Time
+	if strings.HasPrefix(start, "in ") {
+		d, err := time.ParseDuration(strings.ReplaceAll(strings.TrimPrefix(start, "in "), " ", ""))
+		if err != nil {
+			return time.Time{}, errors.New("couldn't read the relative start time; try in 3h or in 1h30m")
+		}
+		t = now.Add(d)
+	} else if clock, err := time.ParseInLocation("15:04", start, loc); err == nil {
+		local := now.In(loc)
+		t = time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+		if !t.After(now) {
+			t = t.AddDate(0, 0, 1)
+		}
+	} else {
+		t, err = time.ParseInLocation("2006-01-02 15:04", start, loc)
+		if err != nil {
+			return time.Time{}, errors.New("start must look like 2026-10-20 21:00, 21:00 or in 3h")
+		}
+	}
+	if !t.After(now) {
+		return time.Time{}, errors.New("start time must be in the future")
+	}
+	if t.Sub(now) > maxSchedule {
+		return time.Time{}, errors.New("events can only be scheduled up to 7 days ahead")
+	}
+	return t, nil
 }
 
 // validMsg checks if a message is within text length
@@ -212,49 +574,53 @@ func queueToFields(user *[]models.QueueUser) []*discordgo.MessageEmbedField {
 	return f
 }
 
+// eventKeys are all arguments an event command accepts
+var eventKeys = []string{"limit", "msg", "start", "waitlist", "mode", "window", "weighted", "minrep", "confirm", "minreliability"}
+
 // parseCmd will attempt to parse a user's set event command.
 //
 // If successful, it will return a pointer to the new event
 //
 // else, it will return nil
 func parseCmd(fullCmd, name, imgURL string) *newEvent {
-	var event newEvent
-	event.Name = name
-	event.Img = imgURL
-	f := func(c rune) bool {
-		return !unicode.IsLetter(c) && !unicode.IsSpace(c) && !unicode.IsNumber(c)
-	}
-	commandBytes := bytes.FieldsFunc([]byte(fullCmd), f)
-	var parsedCmd []string
-	for _, e := range commandBytes {
-		parsedCmd = append(parsedCmd, strings.ToLower(strings.TrimSpace(string(e))))
-	}
-	if !validEvent(parsedCmd) || len(parsedCmd) != 4 {
+	return eventFromArgs(parseArgs(fullCmd), name, imgURL)
+}
+
+// eventFromArgs builds a new event from parsed key="value" arguments; it
+// returns nil if the arguments aren't a valid event
+func eventFromArgs(args map[string]string, name, imgURL string) *newEvent {
+	if !validEvent(args) {
 		return nil
 	}
-	for i := 0; i < len(parsedCmd); i += 2 {
-		if strings.ToLower(parsedCmd[i]) == "limit" {
-			event.Limit = strings.ToUpper(parsedCmd[i+1])
-		}
-		if strings.ToLower(parsedCmd[i]) == "msg" {
-			event.Msg = strings.Title(parsedCmd[i+1])
-		}
+	return &newEvent{
+		Name:     name,
+		Img:      imgURL,
+		Limit:    args["limit"],
+		Msg:      strings.Title(strings.ToLower(args["msg"])),
+		Start:    args["start"],
+		Waitlist: args["waitlist"],
+		Mode:     args["mode"],
+		Window:   args["window"],
+		Weighted: args["weighted"],
+		MinRep:   args["minrep"],
+
+		Confirm:        args["confirm"],
+		MinReliability: args["minreliability"],
 	}
-	return &event
 }
 
 // validEvent will check if the user set enough fields with valid naming.
 //
-// i.e. a validEvent must have two keywords (ONLY), limit and msg
-func validEvent(args []string) bool {
-	var set []string
-	for _, e := range args {
-		if e == "limit" || e == "msg" {
-			set = append(set, e)
-		}
-	}
-	if len(set) != 2 {
+// i.e. a validEvent must have the two keywords limit and msg and may only
+// contain other keys listed in eventKeys
+func validEvent(args map[string]string) bool {
+	if args == nil || args["limit"] == "" || args["msg"] == "" {
 		return false
 	}
+	for k := range args {
+		if !contains(eventKeys, k) {
+			return false
+		}
+	}
 	return true
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestParseEvent(t *testing.T) {
@@ -34,3 +35,131 @@ func TestParseEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStart(t *testing.T) {
+	loc := time.FixedZone("UTC-7", -7*60*60)
+	now := time.Date(2026, 10, 19, 18, 0, 0, 0, loc)
+	tests := map[string]struct {
+		start   string
+		want    time.Time
+		wantErr bool
+	}{
+		"full date": {
+			start: "2026-10-20 21:00",
+			want:  time.Date(2026, 10, 20, 21, 0, 0, 0, loc),
+		},
+		"relative": {
+			start: "in 3h",
+			want:  now.Add(3 * time.Hour),
+		},
+		"relative with spaces": {
+			start: "IN 1h 30m",
+			want:  now.Add(90 * time.Minute),
+		},
+		"time later today": {
+			start: "21:00",
+			want:  time.Date(2026, 10, 19, 21, 0, 0, 0, loc),
+		},
+		"time already passed today": {
+			start: "09:30",
+			want:  time.Date(2026, 10, 20, 9, 30, 0, 0, loc),
+		},
+		"in the past": {
+			start:   "2026-10-18 21:00",
+			wantErr: true,
+		},
+		"too far ahead": {
+			start:   "2026-11-20 21:00",
+			wantErr: true,
+		},
+		"garbage": {
+			start:   "tomorrow-ish",
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseStart(tc.start, loc, now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseStart() err = %v; wantErr %v", err, tc.wantErr)
+			}
+			if !got.Equal(tc.want) {
+				t.Errorf("parseStart() got = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEstimateWait(t *testing.T) {
+	since := time.Date(2026, 10, 19, 20, 0, 0, 0, time.UTC)
+	now := since.Add(30 * time.Minute)
+	tests := map[string]struct {
+		pos        int
+		departures []time.Time
+		want       time.Duration
+		wantOk     bool
+	}{
+		"no departures": {
+			pos:    2,
+			wantOk: false,
+		},
+		"three departures": {
+			pos:        2,
+			departures: []time.Time{since.Add(5 * time.Minute), since.Add(15 * time.Minute), since.Add(25 * time.Minute)},
+			want:       20 * time.Minute,
+			wantOk:     true,
+		},
+		"departures before opening are ignored": {
+			pos:        1,
+			departures: []time.Time{since.Add(-time.Minute), since.Add(10 * time.Minute)},
+			want:       30 * time.Minute,
+			wantOk:     true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := estimateWait(tc.pos, since, tc.departures, now)
+			if ok != tc.wantOk || got != tc.want {
+				t.Errorf("estimateWait() got = %v, %v; want %v, %v", got, ok, tc.want, tc.wantOk)
+			}
+		})
+	}
+}
+
+func TestEventWithoutConfirm(t *testing.T) {
+	event := parseCmd("limit=\"5\" msg=\"ironwood bed\"", "DIY", errThumbURL)
+	if event == nil {
+		t.Fatalf("parseCmd() got = nil; want event")
+	}
+	got, err := parseConfirm(event.Confirm)
+	if err != nil || got != 0 {
+		t.Errorf("parseConfirm() got = %v, %v; want 0, nil", got, err)
+	}
+}
+
+func TestParseConfirm(t *testing.T) {
+	tests := map[string]struct {
+		confirm string
+		want    time.Duration
+		wantErr bool
+	}{
+		"not set": {confirm: "", want: 0},
+		"off":     {confirm: "OFF", want: 0},
+		"minutes": {confirm: "3m", want: 3 * time.Minute},
+		"spaces":  {confirm: "1m 30s", want: 90 * time.Second},
+		"short":   {confirm: "30s", wantErr: true},
+		"long":    {confirm: "1h", wantErr: true},
+		"garbage": {confirm: "soon", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseConfirm(tc.confirm)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseConfirm() err = %v; wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("parseConfirm() got = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"strings"
+
+	"github.com/yiping-allison/isabelle/models"
 )
 
 // Help defines the bot's help command
@@ -42,7 +44,25 @@ func Help(cmdInfo CommandInfo) {
 		msg := cmdInfo.createMsgEmbed("Event", helpThumbURL, "Creates visitation events.",
 			helpColor, format(
 				createFields("EXAMPLE", cmdInfo.Prefix+"event celeste limit=\"2\" msg=\"Come on over for shooting stars\"", false),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event meteor start=\"2026-10-20 21:00\" limit=\"5\" msg=\"Meteor shower night\"", false),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event daisymae start=\"in 3h\" limit=\"5\" msg=\"Sunday turnips\"", false),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event diy limit=\"5\" waitlist=\"5\" msg=\"ironwood bed\"", false),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event turnip limit=\"20\" mode=\"lottery\" window=\"15m\" weighted=\"yes\" msg=\"600 bells\"", false),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event celeste limit=\"5\" minrep=\"3\" msg=\"wishing on stars\"", false),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event celeste limit=\"5\" confirm=\"3m\" minreliability=\"80\" msg=\"wishing on stars\"", false),
 				createFields("EXAMPLE", cmdInfo.Prefix+"event 1234", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event limit 1234 10", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event save swap diy limit=\"5\" msg=\"nightly diy swap\"", false),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event run swap", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event run swap start=\"in 1h\"", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event repeat swap daily 21:00", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event repeat swap sun,wed 9:30", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event repeat swap off", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event templates", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event delete swap", true),
+				createFields("NOTE", "With confirm=\"1m\"-\"30m\", called users must "+cmdInfo.Prefix+"confirm in time or they're skipped as a no-show.", false),
+				createFields("NOTE", "Repeating events are posted an hour before their queue opens.", false),
+				createFields("NOTE", "Start times are read in your timezone; see "+cmdInfo.Prefix+"help timezone", false),
 			))
 		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
 
@@ -50,6 +70,10 @@ func Help(cmdInfo CommandInfo) {
 		msg := cmdInfo.createMsgEmbed("Queue", helpThumbURL, "Join a queue for visitation events.",
 			helpColor, format(
 				createFields("EXAMPLE", cmdInfo.Prefix+"queue 1234", true),
+				createFields("NOTE", "You can also react "+JoinEmoji+" to an event listing to queue and remove the reaction to unregister.", false),
+				createFields("NOTE", "Queueing for an upcoming event reminds you before it opens.", false),
+				createFields("NOTE", "If the queue is full and the event has a waitlist, you're added to the waitlist.", false),
+				createFields("NOTE", "For lottery events, queueing enters the draw while entries are open.", false),
 			))
 		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
 
@@ -88,7 +112,8 @@ func Help(cmdInfo CommandInfo) {
 	case "rep":
 		msg := cmdInfo.createMsgEmbed("Rep", helpThumbURL, "Creates a new reputation application.",
 			helpColor, format(
-				createFields("EXAMPLE", cmdInfo.Prefix+"rep @awesome-person successfully traded coffee beans", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"rep @awesome-person 1234 successfully traded coffee beans", true),
+				createFields("NOTE", "The ID is the event or trade the host confirmed with "+cmdInfo.Prefix+"host visited or "+cmdInfo.Prefix+"trade complete.", false),
 			))
 		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
 
@@ -96,6 +121,23 @@ func Help(cmdInfo CommandInfo) {
 		msg := cmdInfo.createMsgEmbed("Trade", helpThumbURL, "Creates a new trade event.",
 			helpColor, format(
 				createFields("EXAMPLE", cmdInfo.Prefix+"trade item=\"blue mountain coffee\" msg=\"looking for geisha coffee\"", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade item=\"gold nugget\" minrep=\"3\" msg=\"looking for nmt\"", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade have=\"3x gold nugget + 10 star fragments\" want=\"2 nmt\"", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade item=\"ironwood kitchenette\" category=\"diy\" msg=\"looking for bells\"", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade item=\"royal crown\" msg=\"best offer\" sealed=\"yes\"", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade lf item=\"royal crown\" have=\"300k bells\" category=\"furniture\"", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade item=\"crown\" msg=\"auction!\" start=\"100k\" increment=\"10k\" duration=\"2h\"", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade accept 1234 @user", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade complete 1234 @user", true),
+				createFields("NOTE", "Accepting an offer closes the trade and lets every offerer know.", false),
+				createFields("DIRECTION", "Trades are for trade (ft) by default. Start with lf to post what you're looking for; "+
+					"lf listings are matched with ft listings and both hosts get a DM.", false),
+				createFields("CATEGORIES", strings.Join(models.TradeCategories, ", ")+". The category picks the listing channel.", false),
+				createFields("SEALED", "Offers to sealed trades are sent to me by DM and only the host and mods can see them.", false),
+				createFields("ITEMS", "Items are matched to the item catalog by name or nickname (e.g. nmt). "+
+					"Misspelled items get suggestions.", false),
+				createFields("AUCTIONS", "A starting bid turns the trade into an auction. The increment defaults to 1,000 bells "+
+					"and the duration to 1h. The highest bid wins automatically when it ends.", false),
 			))
 		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
 
@@ -103,6 +145,161 @@ func Help(cmdInfo CommandInfo) {
 		msg := cmdInfo.createMsgEmbed("Offer", helpThumbURL, "Provide an offer to a trade event.",
 			helpColor, format(
 				createFields("EXAMPLE", cmdInfo.Prefix+"offer 1234 geisha coffee beans", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"offer 1234 2x nmt + 50k bells", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"offer accept 1234", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"offer withdraw 1234", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"offer edit 1234 3x nmt", true),
+				createFields("NOTE", "Separate items with commas or plus signs and add quantities like 3x. "+
+					"Use accept to take the host's counter-offer and edit to change your offer; the host sees your earlier offers. Offers to sealed trades must be sent to me by DM.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "timezone":
+		msg := cmdInfo.createMsgEmbed("Timezone", helpThumbURL, "Sets the timezone used for your event times.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"timezone America/New_York", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"timezone", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "host":
+		msg := cmdInfo.createMsgEmbed("Host", helpThumbURL, "Manage the queue of your own event. All actions are logged for the mods.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"host next 1234", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"host visited 1234 @user", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"host kick 1234 @user", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"host move 1234 @user 1", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"host lock 1234", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"host unlock 1234", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"host block @user", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"host unblock @user", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "minrep":
+		msg := cmdInfo.createMsgEmbed("MinRep", helpThumbURL, "Sets the server wide minimum reputation to join an event type.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"minrep celeste 5", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"minrep", true),
+				createFields("NOTE", "Changing minimums is only available to moderators.", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "events":
+		msg := cmdInfo.createMsgEmbed("Events", helpThumbURL, "Lists all active events.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"events", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"events celeste", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"events diy 2", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "trades":
+		msg := cmdInfo.createMsgEmbed("Trades", helpThumbURL, "Lists all active trades.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"trades", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trades gold nugget", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trades have gold nugget", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trades want nmt", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trades coffee 2", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trades lf diy", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trades history @user", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trades lookup 1234", true),
+				createFields("NOTE", "Filter by ft or lf and a category before the keyword. History lists completed trades. Only mods can look up a closed trade by ID.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "cohost":
+		msg := cmdInfo.createMsgEmbed("CoHost", helpThumbURL, "Add or remove co-hosts of your event.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"cohost add 1234 @user", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"cohost remove 1234 @user", true),
+				createFields("NOTE", "Co-hosts can manage the queue, close the event and are pinged when people join.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "position":
+		msg := cmdInfo.createMsgEmbed("Position", helpThumbURL, "Shows your place in line and estimated wait.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"position", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"position 1234", true),
+				createFields("NOTE", "The estimate is also sent to your DMs and refreshed as you move up.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "history":
+		msg := cmdInfo.createMsgEmbed("History", helpThumbURL, "Shows the events a user hosted and visited.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"history", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"history @user", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"history top celeste", true),
+				createFields("NOTE", "Only mods can list the top hosts of an event type.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "subscribe":
+		msg := cmdInfo.createMsgEmbed("Subscribe", helpThumbURL, "Get a DM when a matching event or trade is listed.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"subscribe event celeste", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"subscribe trade \"gold nugget\"", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"subscribe quiet 22:00 08:00", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"subscribe quiet off", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"subscribe", true),
+				createFields("NOTE", "Quiet hours use your timezone; no messages are sent during them.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "unsubscribe":
+		msg := cmdInfo.createMsgEmbed("Unsubscribe", helpThumbURL, "Stop getting DMs about listings.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"unsubscribe event celeste", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"unsubscribe trade gold nugget", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"unsubscribe", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "confirm":
+		msg := cmdInfo.createMsgEmbed("Confirm", helpThumbURL, "Confirms you're on your way after being called from a queue.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"confirm 1234", true),
+				createFields("NOTE", "If you don't confirm in time you're skipped and it counts against your reliability.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "wishlist":
+		msg := cmdInfo.createMsgEmbed("Wishlist", helpThumbURL, "Keeps a list of items you're looking for.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"wishlist", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"wishlist add \"geisha coffee\"", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"wishlist remove \"geisha coffee\"", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"wishlist remove", true),
+				createFields("NOTE", "You're messaged when a matching trade is listed. Using remove without an item clears your wishlist.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "bid":
+		msg := cmdInfo.createMsgEmbed("Bid", helpThumbURL, "Places a bell bid on an auction trade.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"bid 1234 50000", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"bid 1234 50k", true),
+				createFields("NOTE", "Bids in the last 2 minutes extend the auction. You're messaged if someone outbids you.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "counter":
+		msg := cmdInfo.createMsgEmbed("Counter", helpThumbURL, "Answers an offer to your trade with a counter-offer.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"counter 1234 @user 3 nmt instead", true),
+				createFields("NOTE", "The offerer can accept with offer accept or withdraw their offer.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+
+	case "decline":
+		msg := cmdInfo.createMsgEmbed("Decline", helpThumbURL, "Turns down an offer to your trade.",
+			helpColor, format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"decline 1234 @user", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"decline 1234 @user already traded the crown", true),
+				createFields("NOTE", "The offerer is messaged and can make a new offer.", false),
 			))
 		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
 
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// historyLimit is the amount of recent events shown in ?history
+	historyLimit = 5
+
+	// topHostsLimit is the amount of hosts shown in ?history top
+	topHostsLimit = 10
+)
+
+// History shows the events a user hosted and visited
+//
+// Moderators can also list the top hosts of an event type.
+//
+// The command usage should look like: ?history @user or ?history top celeste
+func History(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) == 3 && strings.ToLower(cmdInfo.CmdOps[1]) == "top" {
+		topHosts(cmdInfo)
+		return
+	}
+	if len(cmdInfo.CmdOps) > 2 {
+		cmdInfo.historySyntaxError()
+		return
+	}
+	user := cmdInfo.Msg.Author
+	if len(cmdInfo.CmdOps) == 2 {
+		u, err := cmdInfo.mentionedUser(cmdInfo.CmdOps[1])
+		if err != nil {
+			cmdInfo.historySyntaxError()
+			return
+		}
+		user = u
+	}
+
+	loc := cmdInfo.Service.Profile.Location(cmdInfo.Msg.Author.ID)
+	hosted, visited := cmdInfo.Service.History.Counts(user.ID)
+	fields := format(
+		createFields("Events Hosted", strconv.Itoa(hosted), true),
+		createFields("Events Visited", strconv.Itoa(visited), true),
+		createFields("Reliability", cmdInfo.reliabilityText(user.ID), true),
+	)
+	var lines []string
+	for _, r := range cmdInfo.Service.History.Hosted(user.ID, historyLimit) {
+		lines = append(lines, r.Name+" - "+formatTime(r.Ended, loc)+" | Visitors: "+strconv.Itoa(r.Visitors)+
+			" | Closed: "+r.Reason)
+	}
+	if len(lines) > 0 {
+		fields = append(fields, createFields("Recently Hosted", strings.Join(lines, "\n"), false))
+	}
+	lines = nil
+	for _, v := range cmdInfo.Service.History.Visits(user.ID, historyLimit) {
+		name := v.EventType
+		if t, ok := eventTypes[v.EventType]; ok {
+			name = t.name
+		}
+		lines = append(lines, name+" - "+formatTime(v.CreatedAt, loc)+" | Host: "+mentionUser(v.HostID))
+	}
+	if len(lines) > 0 {
+		fields = append(fields, createFields("Recently Visited", strings.Join(lines, "\n"), false))
+	}
+	msg := cmdInfo.createMsgEmbed("Event History", listThumbURL, user.Mention(), listColor, fields)
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+}
+
+// topHosts lists the hosts with the most events of an event type
+func topHosts(cmdInfo CommandInfo) {
+	if !isAdmin(cmdInfo.Msg.Member.Roles, cmdInfo.AdminRole) {
+		// must be admin to see host stats
+		return
+	}
+	eventName := strings.ToLower(cmdInfo.CmdOps[2])
+	t, ok := eventTypes[eventName]
+	if !ok {
+		var types []string
+		for k := range eventTypes {
+			types = append(types, k)
+		}
+		sort.Strings(types)
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Unknown Event Type", errThumbURL, "Try checking your syntax.", errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"history top celeste", true),
+				createFields("Event Types", strings.Join(types, ", "), false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	var fields []*discordgo.MessageEmbedField
+	for i, s := range cmdInfo.Service.History.TopHosts(eventName, topHostsLimit) {
+		fields = append(fields, createFields(strconv.Itoa(i+1)+".", mentionUser(s.HostID)+" - "+strconv.Itoa(s.Events)+" events", false))
+	}
+	if len(fields) == 0 {
+		fields = append(fields, createFields("None", "Nobody has hosted this event type yet.", false))
+	}
+	msg := cmdInfo.createMsgEmbed("Top Hosts: "+t.name, listThumbURL, "Closed and expired events", listColor, fields)
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+}
+
+// historySyntaxError prints all history command examples
+func (c CommandInfo) historySyntaxError() {
+	msg := c.createMsgEmbed(
+		"Error: Wrong Arguments", errThumbURL, "Try checking your syntax.", errColor,
+		format(
+			createFields("EXAMPLE", c.Prefix+"history", true),
+			createFields("EXAMPLE", c.Prefix+"history @user", true),
+			createFields("EXAMPLE", c.Prefix+"history top celeste", true),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
+)
+
+// Host gives event hosts moderation tools for their own queues
+//
+// Every action is posted to the log channel so mods can review disputes.
+//
+// The command usage should look like: ?host kick 1234 @user
+func Host(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) < 3 {
+		cmdInfo.hostSyntaxError()
+		return
+	}
+	switch strings.ToLower(cmdInfo.CmdOps[1]) {
+	case "kick":
+		hostKick(cmdInfo)
+	case "block":
+		hostBlock(cmdInfo, true)
+	case "unblock":
+		hostBlock(cmdInfo, false)
+	case "lock":
+		hostLock(cmdInfo, true)
+	case "unlock":
+		hostLock(cmdInfo, false)
+	case "move":
+		hostMove(cmdInfo)
+	case "next":
+		hostNext(cmdInfo)
+	case "visited":
+		hostVisited(cmdInfo)
+	default:
+		cmdInfo.hostSyntaxError()
+	}
+}
+
+// hostSyntaxError prints all host command examples
+func (c CommandInfo) hostSyntaxError() {
+	msg := c.createMsgEmbed(
+		"Error: Wrong Arguments", errThumbURL, "Try checking your syntax.", errColor,
+		format(
+			createFields("EXAMPLE", c.Prefix+"host next 1234", true),
+			createFields("EXAMPLE", c.Prefix+"host visited 1234 @user", true),
+			createFields("EXAMPLE", c.Prefix+"host kick 1234 @user", true),
+			createFields("EXAMPLE", c.Prefix+"host move 1234 @user 1", true),
+			createFields("EXAMPLE", c.Prefix+"host lock 1234", true),
+			createFields("EXAMPLE", c.Prefix+"host unlock 1234", true),
+			createFields("EXAMPLE", c.Prefix+"host block @user", true),
+			createFields("EXAMPLE", c.Prefix+"host unblock @user", true),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// canManage returns true if the event exists and the message author is allowed
+// to manage it (host or admin); otherwise it prints an error
+func (c CommandInfo) canManage(eventID string) bool {
+	if !c.Service.Event.EventExists(eventID) {
+		msg := c.createMsgEmbed(
+			"Error: Event Not Found", errThumbURL, "Event ID: "+eventID, errColor,
+			format(
+				createFields("Suggestion", "Try checking if you supplied a valid Event ID.", false),
+			))
+		c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+		return false
+	}
+	if !c.Service.Event.IsHost(eventID, c.Msg.Author.ID) && !isAdmin(c.Msg.Member.Roles, c.AdminRole) {
+		msg := c.createMsgEmbed(
+			"Error: You do not have permission to manage this event", errThumbURL, "Event ID: "+eventID, errColor,
+			format(
+				createFields("Suggestion", "Only the host and co-hosts can manage their queue.", false),
+			))
+		c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+		return false
+	}
+	return true
+}
+
+// hostNext calls the first user in the queue to visit
+func hostNext(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) != 3 {
+		cmdInfo.hostSyntaxError()
+		return
+	}
+	eventID := cmdInfo.CmdOps[2]
+	if !cmdInfo.canManage(eventID) {
+		return
+	}
+	cmdInfo.callNext(eventID)
+}
+
+// callNext calls the first user in an event's queue and asks them to confirm
+// they're coming if the event has a confirm window
+func (c CommandInfo) callNext(eventID string) {
+	next, promoted, err := c.Service.Event.Next(eventID)
+	if err != nil {
+		msg := c.createMsgEmbed(
+			"Error: Couldn't Advance Queue", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("Queue ID", eventID, true),
+			))
+		c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+		return
+	}
+	// admitted users no longer count towards their max queues
+	c.Service.User.RemoveQueue(eventID, next)
+
+	e := c.Service.Event.GetEvent(eventID)
+	note := "Please get ready to visit and check your messages for details from the host."
+	if e.ConfirmWindow > 0 {
+		note = "Please confirm you're coming with " + c.Prefix + "confirm " + eventID + " within " +
+			formatDuration(e.ConfirmWindow) + " or you'll be skipped."
+	}
+	msg := c.createMsgEmbed(
+		"It's Your Turn!", checkThumbURL, "Queue ID: "+eventID, successColor,
+		format(
+			createFields("Event", e.Name, true),
+			createFields("Hosted By", c.hostMentions(eventID), true),
+			createFields("Note", note, false),
+		))
+	c.sendDM(next.ID, msg)
+	c.Ses.ChannelMessageSendComplex(c.BotChID, &discordgo.MessageSend{
+		Content: next.Mention() + ": It's your turn!",
+		Embed:   msg,
+	})
+	if promoted != nil {
+		c.notifyPromoted(eventID, []*discordgo.User{promoted})
+	}
+	c.refreshPositions(eventID)
+}
+
+// hostVisited confirms that a user visited the event which lets the host and
+// the visitor file rep applications about each other
+func hostVisited(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) != 4 {
+		cmdInfo.hostSyntaxError()
+		return
+	}
+	eventID := cmdInfo.CmdOps[2]
+	if !cmdInfo.canManage(eventID) {
+		return
+	}
+	userID := stripPing(cmdInfo.CmdOps[3])
+	e := cmdInfo.Service.Event.GetEvent(eventID)
+	if !containsQueueUser(userID, e.Admitted) && !containsQueueUser(userID, e.Queue) {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: User Not In Queue", errThumbURL, "Event ID: "+eventID, errColor,
+			format(
+				createFields("User", mentionUser(userID), true),
+				createFields("Suggestion", "Only users who were in your queue can be marked as visited.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	cmdInfo.confirmInteraction(&models.Interaction{
+		Kind:      models.InteractionVisit,
+		ListingID: eventID,
+		Name:      e.Name,
+		HostID:    e.DiscordUser.ID,
+		UserID:    userID,
+	})
+}
+
+// hostKick removes a user from the host's queue or waitlist
+func hostKick(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) != 4 {
+		cmdInfo.hostSyntaxError()
+		return
+	}
+	eventID := cmdInfo.CmdOps[2]
+	if !cmdInfo.canManage(eventID) {
+		return
+	}
+	userID := stripPing(cmdInfo.CmdOps[3])
+	user := &discordgo.User{ID: userID}
+	e := cmdInfo.Service.Event.GetEvent(eventID)
+	if !containsQueueUser(userID, e.Queue) && !containsQueueUser(userID, e.Waitlist) && !containsQueueUser(userID, e.Entries) {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: User Not In Queue", errThumbURL, "Event ID: "+eventID, errColor,
+			format(
+				createFields("User", mentionUser(userID), true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+
+	promoted := cmdInfo.Service.Event.Remove(eventID, user)
+	cmdInfo.Service.User.RemoveQueue(eventID, user)
+
+	msg := cmdInfo.createMsgEmbed(
+		"Removed From Queue", errThumbURL, "Queue ID: "+eventID, errColor,
+		format(
+			createFields("Hosted By", e.DiscordUser.Mention(), true),
+			createFields("Note", "The host removed you from their queue. If you think this is a mistake, please PM the mods.", false),
+		))
+	cmdInfo.sendDM(userID, msg)
+	embed := cmdInfo.createMsgEmbed(
+		"User Kicked From Queue", checkThumbURL, "Queue ID: "+eventID, successColor,
+		format(
+			createFields("User", mentionUser(userID), true),
+		))
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, embed)
+	cmdInfo.logAction("Kick",
+		createFields("Event ID", eventID, true),
+		createFields("User", mentionUser(userID), true),
+	)
+	if promoted != nil {
+		cmdInfo.notifyPromoted(eventID, []*discordgo.User{promoted})
+	}
+	cmdInfo.refreshPositions(eventID)
+}
+
+// hostBlock blocks or unblocks a user from all of the host's future events
+func hostBlock(cmdInfo CommandInfo, block bool) {
+	if len(cmdInfo.CmdOps) != 3 {
+		cmdInfo.hostSyntaxError()
+		return
+	}
+	host := cmdInfo.Msg.Author
+	userID := stripPing(cmdInfo.CmdOps[2])
+	action := "Blocked"
+	var err error
+	if block {
+		err = cmdInfo.Service.Block.Block(host.ID, userID)
+	} else {
+		action = "Unblocked"
+		err = cmdInfo.Service.Block.Unblock(host.ID, userID)
+	}
+	if err != nil {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Update Block List", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("User", mentionUser(userID), true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	msg := cmdInfo.createMsgEmbed(
+		"User "+action, checkThumbURL, "Applies to all of your events.", successColor,
+		format(
+			createFields("Host", host.Mention(), true),
+			createFields("User", mentionUser(userID), true),
+		))
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+	cmdInfo.logAction(action,
+		createFields("Host", host.Mention(), true),
+		createFields("User", mentionUser(userID), true),
+	)
+}
+
+// hostLock locks or unlocks the host's queue
+func hostLock(cmdInfo CommandInfo, lock bool) {
+	if len(cmdInfo.CmdOps) != 3 {
+		cmdInfo.hostSyntaxError()
+		return
+	}
+	eventID := cmdInfo.CmdOps[2]
+	if !cmdInfo.canManage(eventID) {
+		return
+	}
+	cmdInfo.Service.Event.SetLocked(eventID, lock)
+	cmdInfo.updateEvent(eventID)
+	action := "Unlocked"
+	if lock {
+		action = "Locked"
+	}
+	msg := cmdInfo.createMsgEmbed(
+		"Queue "+action, checkThumbURL, "Queue ID: "+eventID, successColor, nil)
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+	cmdInfo.logAction(action+" Queue",
+		createFields("Event ID", eventID, true),
+	)
+}
+
+// hostMove places a queued user at a new position
+func hostMove(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) != 5 {
+		cmdInfo.hostSyntaxError()
+		return
+	}
+	eventID := cmdInfo.CmdOps[2]
+	if !cmdInfo.canManage(eventID) {
+		return
+	}
+	userID := stripPing(cmdInfo.CmdOps[3])
+	pos, err := strconv.Atoi(cmdInfo.CmdOps[4])
+	if err == nil {
+		err = cmdInfo.Service.Event.Move(eventID, &discordgo.User{ID: userID}, pos)
+	}
+	if err != nil {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Move User", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"host move 1234 @user 1", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	queue := cmdInfo.Service.Event.GetQueue(eventID)
+	msg := cmdInfo.createMsgEmbed(
+		"Current Queue", queueThumbURL, "Queue ID: "+eventID, eventColor, queueToFields(queue))
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+	cmdInfo.logAction("Move",
+		createFields("Event ID", eventID, true),
+		createFields("User", mentionUser(userID), true),
+		createFields("Position", strconv.Itoa(pos), true),
+	)
+	cmdInfo.refreshPositions(eventID)
+}
+
+// containsQueueUser returns true if the user ID is found in the queue
+func containsQueueUser(userID string, queue []models.QueueUser) bool {
+	for _, u := range queue {
+		if u.DiscordUser.ID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yiping-allison/isabelle/models"
+)
+
+const (
+	// maxLines is the amount of lines one side of a trade or an offer can have
+	maxLines = 10
+
+	// maxQuantity is the largest quantity a line can have
+	maxQuantity = 999
+
+	// bellsItem is the item of a bell amount line; its quantity is the
+	// amount of bells
+	bellsItem = "bells"
+)
+
+var (
+	// quantity patterns of a trade line (e.g. 3x gold nugget, 3 gold nugget
+	// or gold nugget x3)
+	linePrefixX = regexp.MustCompile(`^(\d+)\s*[x×]\s+(.+)$`)
+	linePrefix  = regexp.MustCompile(`^(\d+)\s+(.+)$`)
+	lineSuffixX = regexp.MustCompile(`^(.+?)\s+[x×]\s*(\d+)$`)
+
+	// bell amounts such as 100,000 bells or 50k bells
+	lineBells = regexp.MustCompile(`^(\d[\d,]*k?)\s*bells?$`)
+)
+
+// parseLines parses a list of trade lines separated by commas or plus signs
+// such as "3x gold nugget + 10 star fragments"
+//
+// Lines without a quantity count once
+func parseLines(str string) ([]models.TradeLine, error) {
+	parts := splitLines(strings.ToLower(str))
+	var ret []models.TradeLine
+	for _, p := range parts {
+		p = strings.Join(strings.Fields(p), " ")
+		if p == "" {
+			continue
+		}
+		line, err := parseLine(p)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, line)
+	}
+	if len(ret) == 0 {
+		return nil, errors.New("need at least one item")
+	}
+	if len(ret) > maxLines {
+		return nil, errors.New("you can list at most " + strconv.Itoa(maxLines) + " items at a time")
+	}
+	return ret, nil
+}
+
+// splitLines splits trade lines on commas, plus signs and semicolons; commas
+// between thousands of a number (e.g. 100,000 bells) don't split
+func splitLines(str string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(str); i++ {
+		switch str[i] {
+		case ',':
+			if thousands(str, i) {
+				continue
+			}
+			fallthrough
+		case '+', ';':
+			parts = append(parts, str[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, str[start:])
+}
+
+// thousands returns true if the comma at i separates the thousands of a
+// number; it must follow a digit and be followed by exactly three digits
+func thousands(str string, i int) bool {
+	if i == 0 || !isDigit(str[i-1]) || i+4 > len(str) {
+		return false
+	}
+	for _, c := range []byte(str[i+1 : i+4]) {
+		if !isDigit(c) {
+			return false
+		}
+	}
+	return i+4 == len(str) || !isDigit(str[i+4])
+}
+
+// isDigit returns true if the byte is an ASCII digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parseLine parses one trade line with an optional quantity; bell amounts are
+// lines of bellsItem with the amount as quantity
+func parseLine(str string) (models.TradeLine, error) {
+	if m := lineBells.FindStringSubmatch(str); m != nil {
+		n, err := parseBells(m[1])
+		if err != nil {
+			return models.TradeLine{}, err
+		}
+		return models.TradeLine{Item: bellsItem, Quantity: n}, nil
+	}
+	qty, item := "1", str
+	if m := linePrefixX.FindStringSubmatch(str); m != nil {
+		qty, item = m[1], m[2]
+	} else if m := linePrefix.FindStringSubmatch(str); m != nil {
+		qty, item = m[1], m[2]
+	} else if m := lineSuffixX.FindStringSubmatch(str); m != nil {
+		item, qty = m[1], m[2]
+	}
+	n, err := strconv.Atoi(qty)
+	if err != nil || n < 1 || n > maxQuantity {
+		return models.TradeLine{}, errors.New("quantities must be between 1 and " + strconv.Itoa(maxQuantity))
+	}
+	return models.TradeLine{Item: item, Quantity: n}, nil
+}
+
+// formatLines prints trade lines such as 3x Gold Nugget or 50,000 bells
+// joined by sep
+func formatLines(lines []models.TradeLine, sep string) string {
+	var ret []string
+	for _, l := range lines {
+		if l.Item == bellsItem {
+			ret = append(ret, formatBells(l.Quantity))
+			continue
+		}
+		ret = append(ret, strconv.Itoa(l.Quantity)+"x "+strings.Title(l.Item))
+	}
+	return strings.Join(ret, sep)
+}
+
+// lineSummary prints the lower case items of trade lines on one line; a
+// single item without a quantity is just its name
+func lineSummary(lines []models.TradeLine) string {
+	if len(lines) == 1 && lines[0].Quantity == 1 && lines[0].Item != bellsItem {
+		return lines[0].Item
+	}
+	return strings.ToLower(formatLines(lines, " + "))
+}
+
+// lineItems returns the items of trade lines
+func lineItems(lines []models.TradeLine) []string {
+	var ret []string
+	for _, l := range lines {
+		ret = append(ret, l.Item)
+	}
+	return ret
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yiping-allison/isabelle/models"
+)
+
+func TestParseLines(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		want    []models.TradeLine
+		wantErr bool
+	}{
+		"single item": {
+			in:   "gold nugget",
+			want: []models.TradeLine{{Item: "gold nugget", Quantity: 1}},
+		},
+		"prefix quantities": {
+			in: "3x Gold Nugget + 10 star fragments",
+			want: []models.TradeLine{
+				{Item: "gold nugget", Quantity: 3},
+				{Item: "star fragments", Quantity: 10},
+			},
+		},
+		"spaced x": {
+			in:   "2 x nmt",
+			want: []models.TradeLine{{Item: "nmt", Quantity: 2}},
+		},
+		"suffix quantity": {
+			in:   "gold nugget x3, nmt",
+			want: []models.TradeLine{{Item: "gold nugget", Quantity: 3}, {Item: "nmt", Quantity: 1}},
+		},
+		"extra spaces and separators": {
+			in:   "  royal   crown ,, ;",
+			want: []models.TradeLine{{Item: "royal crown", Quantity: 1}},
+		},
+		"item starting with x": {
+			in:   "2 xylophone",
+			want: []models.TradeLine{{Item: "xylophone", Quantity: 2}},
+		},
+		"bells": {
+			in:   "1000 bells",
+			want: []models.TradeLine{{Item: "bells", Quantity: 1000}},
+		},
+		"bells with thousands separators": {
+			in: "100,000 bells, 2 nmt",
+			want: []models.TradeLine{
+				{Item: "bells", Quantity: 100000},
+				{Item: "nmt", Quantity: 2},
+			},
+		},
+		"short bells": {
+			in:   "2x nmt + 50k bells",
+			want: []models.TradeLine{{Item: "nmt", Quantity: 2}, {Item: "bells", Quantity: 50000}},
+		},
+		"comma between quantities": {
+			in:   "gold nugget x3,5 nmt",
+			want: []models.TradeLine{{Item: "gold nugget", Quantity: 3}, {Item: "nmt", Quantity: 5}},
+		},
+		"zero quantity": {
+			in:      "0x gold nugget",
+			wantErr: true,
+		},
+		"huge quantity": {
+			in:      "1000 gold nugget",
+			wantErr: true,
+		},
+		"empty": {
+			in:      " + ",
+			wantErr: true,
+		},
+		"too many lines": {
+			in:      "a, b, c, d, e, f, g, h, i, j, k",
+			wantErr: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseLines(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseLines() err = %v; wantErr %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseLines() got = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -12,9 +12,24 @@ func List(cmdInfo CommandInfo) {
 		createFields("unregister", cmdInfo.Prefix+"unregister ...", true),
 		createFields("trade", cmdInfo.Prefix+"trade ...", true),
 		createFields("offer", cmdInfo.Prefix+"offer ...", true),
+		createFields("counter", cmdInfo.Prefix+"counter ...", true),
+		createFields("decline", cmdInfo.Prefix+"decline ...", true),
+		createFields("bid", cmdInfo.Prefix+"bid ...", true),
 		createFields("accept", cmdInfo.Prefix+"accept ...", true),
 		createFields("reject", cmdInfo.Prefix+"reject ...", true),
 		createFields("rep", cmdInfo.Prefix+"rep ...", true),
+		createFields("timezone", cmdInfo.Prefix+"timezone ...", true),
+		createFields("host", cmdInfo.Prefix+"host ...", true),
+		createFields("minrep", cmdInfo.Prefix+"minrep ...", true),
+		createFields("events", cmdInfo.Prefix+"events ...", true),
+		createFields("trades", cmdInfo.Prefix+"trades ...", true),
+		createFields("cohost", cmdInfo.Prefix+"cohost ...", true),
+		createFields("position", cmdInfo.Prefix+"position ...", true),
+		createFields("history", cmdInfo.Prefix+"history ...", true),
+		createFields("subscribe", cmdInfo.Prefix+"subscribe ...", true),
+		createFields("unsubscribe", cmdInfo.Prefix+"unsubscribe ...", true),
+		createFields("confirm", cmdInfo.Prefix+"confirm ...", true),
+		createFields("wishlist", cmdInfo.Prefix+"wishlist ...", true),
 	)
 	msg := cmdInfo.createMsgEmbed("Commands", listThumbURL, "", listColor, fields)
 	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
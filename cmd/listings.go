@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
+)
+
+const (
+	// pageSize is the amount of listings shown per page
+	pageSize int = 8
+)
+
+// Events lists every active event, optionally filtered by event type
+//
+// The command usage should look like: ?events [type] [page]
+func Events(cmdInfo CommandInfo) {
+	args, page := pageArg(cmdInfo.CmdOps[1:])
+	var events []models.EventData
+	desc := "All event types"
+	switch len(args) {
+	case 0:
+		events = cmdInfo.Service.Event.All()
+	case 1:
+		eventName := strings.ToLower(args[0])
+		t, ok := eventTypes[eventName]
+		if !ok {
+			msg := cmdInfo.createMsgEmbed(
+				"Error: Unknown Event Type", errThumbURL, args[0], errColor,
+				format(
+					createFields("EXAMPLE", cmdInfo.Prefix+"events celeste", true),
+					createFields("EXAMPLE", cmdInfo.Prefix+"events 2", true),
+				))
+			cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+			return
+		}
+		events = cmdInfo.Service.Event.ByType(eventName)
+		desc = t.name
+	default:
+		return
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	for _, e := range events {
+		rep := cmdInfo.Service.Rep.GetRep(e.DiscordUser.ID)
+		status := "Slots Left: " + strconv.Itoa(e.Limit-len(e.Queue)) + "/" + strconv.Itoa(e.Limit)
+		switch {
+		case !e.Open:
+			status = "Opens in " + formatDuration(time.Until(e.Start))
+		case e.Lottery && !e.Drawn:
+			status = "Lottery Entries: " + strconv.Itoa(len(e.Entries))
+		case e.Locked:
+			status = "Locked"
+		}
+		fields = append(fields, createFields(
+			e.ID+" - "+e.Name,
+			"Host: "+e.DiscordUser.Mention()+" (Rep: "+strconv.Itoa(rep)+")\n"+
+				status+" | Expires in "+formatDuration(time.Until(e.Expiration)),
+			false,
+		))
+	}
+	cmdInfo.printPage("Active Events", queueThumbURL, desc, eventColor, fields, page)
+}
+
+// Trades lists every active trade, optionally filtered by a keyword
+//
+// Completed trades can be paged through with the history subcommand and
+// moderators can look up a closed trade by ID.
+//
+// Trades can be filtered by direction and category before the keyword.
+//
+// The command usage should look like: ?trades [ft|lf] [category] [keyword] [page]
+func Trades(cmdInfo CommandInfo) {
+	// subcommands split off their own page so arguments such as trade IDs
+	// aren't taken as page numbers
+	if len(cmdInfo.CmdOps) > 1 {
+		args := cmdInfo.CmdOps[2:]
+		switch sub := strings.ToLower(cmdInfo.CmdOps[1]); sub {
+		case models.LineHave, models.LineWant:
+			searchLines(cmdInfo, sub, args)
+			return
+		case "history":
+			tradeHistory(cmdInfo, args)
+			return
+		case "lookup":
+			lookupTrade(cmdInfo, args)
+			return
+		}
+	}
+	args, page := pageArg(cmdInfo.CmdOps[1:])
+	direction, category, args := parseTradeFilters(args)
+	var trades []models.TradeData
+	desc := "All trades"
+	if len(args) == 0 {
+		trades = cmdInfo.Service.Trade.All()
+	} else {
+		keyword := strings.Join(args, " ")
+		trades = cmdInfo.Service.Trade.Search(keyword)
+		desc = "Matching: " + keyword
+	}
+	if direction != "" || category != "" {
+		trades = filterTrades(trades, direction, category)
+		desc += " | Filters: " + strings.TrimSpace(strings.ToUpper(direction)+" "+category)
+	}
+
+	cmdInfo.printTrades(trades, desc, page)
+}
+
+// searchLines lists every active trade with a matching line on one side
+//
+// The command usage should look like: ?trades have gold nugget [page]
+func searchLines(cmdInfo CommandInfo, side string, args []string) {
+	args, page := pageArg(args)
+	if len(args) == 0 {
+		cmdInfo.tradesSyntaxError()
+		return
+	}
+	keyword := strings.Join(args, " ")
+	trades := cmdInfo.Service.Trade.SearchLines(side, keyword)
+	cmdInfo.printTrades(trades, strings.Title(side)+": "+keyword, page)
+}
+
+// printTrades prints one page of active trades
+func (c CommandInfo) printTrades(trades []models.TradeData, desc string, page int) {
+	var fields []*discordgo.MessageEmbedField
+	for _, t := range trades {
+		rep := c.Service.Rep.GetRep(t.DiscordUser.ID)
+		status := "Offers: " + strconv.Itoa(openOffers(t)) + " | Expires in " + formatDuration(time.Until(t.Expiration))
+		if t.Auction {
+			status = "Next Bid: " + formatBells(t.MinBid()) + " | Ends in " + formatDuration(time.Until(t.Ends))
+		}
+		want := ""
+		if len(t.Want) > 0 {
+			want = "Want: " + formatLines(t.Want, ", ") + "\n"
+		}
+		title := t.ID + " - " + strings.Title(t.Item)
+		if t.Direction == models.TradeLF {
+			title = t.ID + " - LF: " + strings.Title(t.Item)
+		}
+		fields = append(fields, createFields(
+			title,
+			"Host: "+t.DiscordUser.Mention()+" (Rep: "+strconv.Itoa(rep)+")\n"+want+status,
+			false,
+		))
+	}
+	c.printPage("Active Trades", tradeThumbURL, desc, tradeColor, fields, page)
+}
+
+// tradeHistory pages through the completed trades of a user
+//
+// The command usage should look like: ?trades history [@user] [page]
+func tradeHistory(cmdInfo CommandInfo, args []string) {
+	args, page := pageArg(args)
+	user := cmdInfo.Msg.Author
+	switch len(args) {
+	case 0:
+	case 1:
+		u, err := cmdInfo.mentionedUser(args[0])
+		if err != nil {
+			cmdInfo.tradesSyntaxError()
+			return
+		}
+		user = u
+	default:
+		cmdInfo.tradesSyntaxError()
+		return
+	}
+	loc := cmdInfo.Service.Profile.Location(cmdInfo.Msg.Author.ID)
+	var fields []*discordgo.MessageEmbedField
+	for _, r := range cmdInfo.Service.Ledger.ByUser(user.ID) {
+		fields = append(fields, cmdInfo.tradeRecordField(r, loc))
+	}
+	if len(fields) == 0 {
+		msg := cmdInfo.createMsgEmbed("Trade History", tradeThumbURL, user.Mention(), tradeColor, format(
+			createFields("Nothing Found", "This user hasn't completed any trades yet.", false),
+		))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	cmdInfo.printPage("Trade History", tradeThumbURL, user.Mention(), tradeColor, fields, page)
+}
+
+// lookupTrade shows the completed trades listed under a trade ID
+//
+// The command usage should look like: ?trades lookup 1234 [page]
+func lookupTrade(cmdInfo CommandInfo, args []string) {
+	if !isAdmin(cmdInfo.Msg.Member.Roles, cmdInfo.AdminRole) {
+		// must be admin to look up closed trades
+		return
+	}
+	if len(args) == 0 {
+		cmdInfo.tradesSyntaxError()
+		return
+	}
+	rest, page := pageArg(args[1:])
+	if len(rest) != 0 {
+		cmdInfo.tradesSyntaxError()
+		return
+	}
+	loc := cmdInfo.Service.Profile.Location(cmdInfo.Msg.Author.ID)
+	var fields []*discordgo.MessageEmbedField
+	for _, r := range cmdInfo.Service.Ledger.ByTradeID(args[0]) {
+		fields = append(fields, cmdInfo.tradeRecordField(r, loc))
+	}
+	if len(fields) == 0 {
+		msg := cmdInfo.createMsgEmbed("Trade Lookup", tradeThumbURL, "Trade ID: "+args[0], tradeColor, format(
+			createFields("Nothing Found", "No completed trade was listed under this ID.", false),
+		))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	cmdInfo.printPage("Trade Lookup", tradeThumbURL, "Trade ID: "+args[0], tradeColor, fields, page)
+}
+
+// tradeRecordField prints a completed trade as one embed field
+func (c CommandInfo) tradeRecordField(r models.TradeRecord, loc *time.Location) *discordgo.MessageEmbedField {
+	admin := c.Msg.Member != nil && isAdmin(c.Msg.Member.Roles, c.AdminRole)
+	return createFields(
+		r.TradeID+" - "+strings.Title(r.Item),
+		"Host: "+mentionUser(r.HostID)+" | Traded With: "+mentionUser(r.UserID)+"\n"+
+			"Offer: "+recordOffer(r, c.Msg.Author.ID, admin)+"\n"+
+			"Completed: "+formatTime(r.CreatedAt, loc),
+		false,
+	)
+}
+
+// recordOffer returns the accepted offer of a completed trade; sealed offers
+// are only shown to the host, the offerer and mods
+func recordOffer(r models.TradeRecord, viewerID string, admin bool) string {
+	if r.Sealed && !admin && viewerID != r.HostID && viewerID != r.UserID {
+		return "Sealed"
+	}
+	return r.Offer
+}
+
+// tradesSyntaxError prints all trades command examples
+func (c CommandInfo) tradesSyntaxError() {
+	msg := c.createMsgEmbed(
+		"Error: Wrong Arguments", errThumbURL, "Try checking your syntax.", errColor,
+		format(
+			createFields("EXAMPLE", c.Prefix+"trades have gold nugget", true),
+			createFields("EXAMPLE", c.Prefix+"trades want nmt", true),
+			createFields("EXAMPLE", c.Prefix+"trades lf diy", true),
+			createFields("EXAMPLE", c.Prefix+"trades history", true),
+			createFields("EXAMPLE", c.Prefix+"trades history @user 2", true),
+			createFields("EXAMPLE", c.Prefix+"trades lookup 1234", true),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// pageArg splits an optional trailing page number off of command arguments
+//
+// Pages start at 1
+func pageArg(args []string) ([]string, int) {
+	if len(args) == 0 {
+		return args, 1
+	}
+	if page, err := strconv.Atoi(args[len(args)-1]); err == nil && page > 0 {
+		return args[:len(args)-1], page
+	}
+	return args, 1
+}
+
+// printPage prints one page of listing fields with the page number in the footer
+func (c CommandInfo) printPage(title, tURL, desc string, color int, fields []*discordgo.MessageEmbedField, page int) {
+	if len(fields) == 0 {
+		msg := c.createMsgEmbed(title, tURL, desc, color, format(
+			createFields("Nothing Found", "There are no active listings right now.", false),
+		))
+		c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+		return
+	}
+	pages := (len(fields) + pageSize - 1) / pageSize
+	if page > pages {
+		page = pages
+	}
+	i := (page - 1) * pageSize
+	j := i + pageSize
+	if j > len(fields) {
+		j = len(fields)
+	}
+	msg := c.createMsgEmbed(title, tURL, desc, color, fields[i:j])
+	msg.Footer = &discordgo.MessageEmbedFooter{
+		Text: "Page " + strconv.Itoa(page) + "/" + strconv.Itoa(pages) + " | " + strconv.Itoa(len(fields)) + " total",
+	}
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// formatDuration prints a duration rounded to minutes (e.g. 1h20m)
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "less than a minute"
+	}
+	str := d.Round(time.Minute).String()
+	return strings.TrimSuffix(str, "0s")
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/yiping-allison/isabelle/models"
+)
+
+func TestRecordOffer(t *testing.T) {
+	sealed := models.TradeRecord{Offer: "3x Nmt", Sealed: true, HostID: "1", UserID: "2"}
+	tests := map[string]struct {
+		record models.TradeRecord
+		viewer string
+		admin  bool
+		want   string
+	}{
+		"open trade": {
+			record: models.TradeRecord{Offer: "3x Nmt", HostID: "1", UserID: "2"},
+			viewer: "3",
+			want:   "3x Nmt",
+		},
+		"sealed host":     {record: sealed, viewer: "1", want: "3x Nmt"},
+		"sealed offerer":  {record: sealed, viewer: "2", want: "3x Nmt"},
+		"sealed admin":    {record: sealed, viewer: "3", admin: true, want: "3x Nmt"},
+		"sealed everyone": {record: sealed, viewer: "3", want: "Sealed"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := recordOffer(tc.record, tc.viewer, tc.admin); got != tc.want {
+				t.Errorf("recordOffer() got = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
+)
+
+const (
+	// minWindow and maxWindow bound the entry window of lottery events
+	minWindow = time.Minute
+	maxWindow = time.Hour
+)
+
+// parseLottery reads the lottery options of an event command
+//
+// It returns whether the event is a lottery, its entry window and whether the
+// draw is weighted by reputation
+func parseLottery(event *newEvent) (bool, time.Duration, bool, error) {
+	switch strings.ToLower(event.Mode) {
+	case "", "queue":
+		if event.Window != "" || event.Weighted != "" {
+			return false, 0, false, errors.New("window and weighted only apply to mode=\"lottery\"")
+		}
+		return false, 0, false, nil
+	case "lottery":
+	default:
+		return false, 0, false, errors.New("mode must be queue or lottery")
+	}
+	window := 15 * time.Minute
+	if event.Window != "" {
+		d, err := time.ParseDuration(strings.ReplaceAll(strings.ToLower(event.Window), " ", ""))
+		if err != nil || d < minWindow || d > maxWindow {
+			return false, 0, false, errors.New("window must be between 1m and 1h")
+		}
+		window = d
+	}
+	var weighted bool
+	switch strings.ToLower(event.Weighted) {
+	case "", "no":
+	case "yes":
+		weighted = true
+	default:
+		return false, 0, false, errors.New("weighted must be yes or no")
+	}
+	return true, window, weighted, nil
+}
+
+// enter records the user's entry to a lottery event
+func (c CommandInfo) enter(eventID string, rep int) {
+	user := c.Msg.Author
+	_, err := c.Service.Event.AddEntry(user, rep, eventID)
+	if err != nil {
+		msg := c.createMsgEmbed(
+			"Error: Couldn't Enter Lottery", errThumbURL, strings.Title(err.Error()),
+			errColor, format(
+				createFields("User", user.Mention(), true),
+				createFields("EXAMPLE", c.Prefix+"queue 1234", true),
+			))
+		c.replyError(msg)
+		return
+	}
+
+	// entries are tracked like queues so unregister and close clean them up
+	if !c.Service.User.UserExists(user) {
+		c.Service.User.AddUser(user)
+	}
+	c.Service.User.AddQueue(eventID, user, c.Service.Event.GetExpiration(eventID))
+
+	e := c.Service.Event.GetEvent(eventID)
+	loc := c.Service.Profile.Location(user.ID)
+	embed := c.createMsgEmbed(
+		"Lottery Entry Recorded!", checkThumbURL, "Queue ID: "+eventID,
+		successColor, format(
+			createFields("User", user.Mention(), true),
+			createFields("Entries", strconv.Itoa(len(e.Entries)), true),
+			createFields("Draw", formatTime(e.Start.Add(e.Window), loc), false),
+			createFields("Note", "The queue order is drawn at random once entries close. You'll be messaged with the result.", false),
+		))
+	cplx := &discordgo.MessageSend{
+		Content: c.hostMentions(eventID) + ": A new person has entered your lottery!",
+		Embed:   embed,
+	}
+	c.Ses.ChannelMessageSendComplex(c.BotChID, cplx)
+}
+
+// drawLotteries draws the queue order of every lottery event whose entry window
+// closed and publishes the result along with its seed
+func (c CommandInfo) drawLotteries() {
+	for _, id := range c.Service.Event.DueDraws() {
+		e := c.Service.Event.GetEvent(id)
+		weights := make([]float64, len(e.Entries))
+		for i, u := range e.Entries {
+			weights[i] = 1
+			if e.Weighted {
+				weights[i] = drawWeight(c.Service.Rep.GetRep(u.DiscordUser.ID))
+			}
+			// repeat no-shows get a lower priority
+			score, _ := c.Service.Attendance.Reliability(u.DiscordUser.ID)
+			weights[i] *= reliabilityWeight(score)
+		}
+		seed := time.Now().UnixNano()
+		order := drawOrder(e.Entries, weights, seed)
+		rest := c.Service.Event.SetDraw(id, seed, order)
+		for _, u := range rest {
+			c.Service.User.RemoveQueue(id, u.DiscordUser)
+		}
+		c.publishDraw(id, e.Entries, order, weights)
+	}
+}
+
+// publishDraw posts the draw result and messages every entrant their outcome
+func (c CommandInfo) publishDraw(eventID string, entries, order []models.QueueUser, weights []float64) {
+	e := c.Service.Event.GetEvent(eventID)
+	var entrants []string
+	for i, u := range entries {
+		entrants = append(entrants, u.DiscordUser.String()+" ("+strconv.FormatFloat(weights[i], 'f', -1, 64)+")")
+	}
+	method := "Weighted shuffle with Go's math/rand seeded by the seed above; each entry (in join order) " +
+		"draws key = rand.Float64()^(1/weight) and the order is by key, highest first. " +
+		"Weights are scaled by attendance reliability."
+	fields := format(
+		createFields("Seed", strconv.FormatInt(e.Seed, 10), true),
+		createFields("Entries", strconv.Itoa(len(order)), true),
+		createFields("Weighted By Reputation", strconv.FormatBool(e.Weighted), true),
+		createFields("Method", method, false),
+	)
+	if len(entrants) > 0 {
+		fields = append(fields, createFields("Entries (Weight)", truncate(strings.Join(entrants, ", "), 1024), false))
+	}
+	fields = append(fields, queueToFields(&e.Queue)...)
+	msg := c.createMsgEmbed("Lottery Drawn: "+e.Name, queueThumbURL, "Queue ID: "+eventID, eventColor, fields)
+	c.Ses.ChannelMessageSendComplex(c.BotChID, &discordgo.MessageSend{
+		Content: c.hostMentions(eventID) + ": Your lottery has been drawn!",
+		Embed:   msg,
+	})
+
+	for i, u := range order {
+		result := "Unfortunately you weren't drawn this time."
+		switch {
+		case i < len(e.Queue):
+			result = "You're number " + strconv.Itoa(i+1) + " in the queue. Please wait until you're pinged or messaged!"
+		case i < len(e.Queue)+len(e.Waitlist):
+			result = "You're number " + strconv.Itoa(i+1-len(e.Queue)) + " on the waitlist."
+		}
+		dm := c.createMsgEmbed(
+			"Lottery Result: "+e.Name, queueThumbURL, "Queue ID: "+eventID, eventColor,
+			format(
+				createFields("Result", result, false),
+				createFields("Seed", strconv.FormatInt(e.Seed, 10), true),
+			))
+		c.sendDM(u.DiscordUser.ID, dm)
+	}
+}
+
+// drawWeight turns a reputation number into a lottery weight
+//
+// Users without reputation still get a weight of 1
+func drawWeight(rep int) float64 {
+	if rep < 0 {
+		rep = 0
+	}
+	return float64(rep + 1)
+}
+
+// drawOrder returns the entries in a random order where entries with higher
+// weights are more likely to be placed first
+//
+// The order only depends on the entries (in join order), their weights and the
+// seed so anyone can reproduce a published draw
+func drawOrder(entries []models.QueueUser, weights []float64, seed int64) []models.QueueUser {
+	r := rand.New(rand.NewSource(seed))
+	keys := make([]float64, len(entries))
+	idx := make([]int, len(entries))
+	for i := range entries {
+		keys[i] = math.Pow(r.Float64(), 1/weights[i])
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return keys[idx[a]] > keys[idx[b]]
+	})
+	ret := make([]models.QueueUser, len(entries))
+	for i, j := range idx {
+		ret[i] = entries[j]
+	}
+	return ret
+}
+
+// truncate shortens a string to fit discord's embed field limits
+func truncate(str string, max int) string {
+	r := []rune(str)
+	if len(r) <= max {
+		return str
+	}
+	return string(r[:max-3]) + "..."
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
+)
+
+func TestDrawOrder(t *testing.T) {
+	var entries []models.QueueUser
+	var weights []float64
+	for i := 0; i < 10; i++ {
+		entries = append(entries, models.QueueUser{DiscordUser: &discordgo.User{ID: strconv.Itoa(i)}})
+		weights = append(weights, drawWeight(i-2))
+	}
+
+	first := drawOrder(entries, weights, 42)
+	if len(first) != len(entries) {
+		t.Fatalf("drawOrder() got %d entries; want %d", len(first), len(entries))
+	}
+	seen := make(map[string]bool)
+	for _, u := range first {
+		seen[u.DiscordUser.ID] = true
+	}
+	if len(seen) != len(entries) {
+		t.Errorf("drawOrder() got duplicate or missing entries: %v", seen)
+	}
+	if again := drawOrder(entries, weights, 42); !reflect.DeepEqual(first, again) {
+		t.Errorf("drawOrder() is not reproducible with the same seed")
+	}
+}
+
+func TestDrawWeight(t *testing.T) {
+	tests := map[string]struct {
+		rep  int
+		want float64
+	}{
+		"not in rep database": {rep: -1, want: 1},
+		"no rep":              {rep: 0, want: 1},
+		"some rep":            {rep: 4, want: 5},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := drawWeight(tc.rep); got != tc.want {
+				t.Errorf("drawWeight() got = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReliabilityWeight(t *testing.T) {
+	tests := map[string]struct {
+		score int
+		want  float64
+	}{
+		"perfect":  {score: 100, want: 1},
+		"half":     {score: 50, want: 0.5},
+		"floor":    {score: 0, want: 0.25},
+		"clamped":  {score: 150, want: 1},
+		"at floor": {score: 25, want: 0.25},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := reliabilityWeight(tc.score); got != tc.want {
+				t.Errorf("reliabilityWeight() got = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
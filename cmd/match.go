@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
+)
+
+// maxMatches is the amount of matching listings shown to a new poster
+const maxMatches = 5
+
+// matchListings pairs a new listing with active listings going the other
+// way; an lf listing matches ft listings which have an item it wants and the
+// other way around
+//
+// The poster gets the matching listings by DM and every matching host is told
+// about the new listing
+func (c CommandInfo) matchListings(tradeID string) {
+	t := c.Service.Trade.GetTrade(tradeID)
+	if t.DiscordUser == nil {
+		return
+	}
+	var fields []*discordgo.MessageEmbedField
+	notified := make(map[string]bool)
+	for _, o := range c.Service.Trade.All() {
+		if o.ID == t.ID || o.Direction == t.Direction || o.DiscordUser.ID == t.DiscordUser.ID {
+			continue
+		}
+		want, have := t.Want, o.Have
+		if t.Direction == models.TradeFT {
+			want, have = o.Want, t.Have
+		}
+		if !linesMatch(want, have) {
+			continue
+		}
+		if len(fields) < maxMatches {
+			fields = append(fields, c.matchField(o))
+		}
+		if !notified[o.DiscordUser.ID] {
+			notified[o.DiscordUser.ID] = true
+			c.notifyUser(o.DiscordUser.ID, t.DiscordUser.ID, c.createMsgEmbed(
+				"Listing Match", tradeThumbURL, "Your listing "+o.ID+" matches a new listing", tradeColor,
+				format(c.matchField(t)),
+			))
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+	c.sendDM(t.DiscordUser.ID, c.createMsgEmbed(
+		"Listing Matches", tradeThumbURL, "Your listing "+t.ID+" matches these listings", tradeColor, fields,
+	))
+}
+
+// matchField prints a matching listing with how to respond to it
+func (c CommandInfo) matchField(t models.TradeData) *discordgo.MessageEmbedField {
+	title := "For Trade: "
+	if t.Direction == models.TradeLF {
+		title = "Looking For: "
+	}
+	return createFields(
+		t.ID+" - "+title+strings.Title(t.Item),
+		"Host: "+t.DiscordUser.Mention()+"\nMake an Offer: "+c.Prefix+"offer "+t.ID+" your offer",
+		false,
+	)
+}
+
+// linesMatch returns true if any wanted line matches a line someone has;
+// catalog items match by ID and free text items by name
+func linesMatch(want, have []models.TradeLine) bool {
+	for _, w := range want {
+		for _, h := range have {
+			if w.ItemID != 0 && h.ItemID != 0 {
+				if w.ItemID == h.ItemID {
+					return true
+				}
+				continue
+			}
+			if matchItem(w.Item, h.Item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseTradeFilters splits leading direction and category filters such as
+// "lf diy" off of the trades command arguments
+func parseTradeFilters(args []string) (string, string, []string) {
+	direction, category := "", ""
+	for len(args) > 0 {
+		a := strings.ToLower(args[0])
+		if direction == "" && (a == models.TradeFT || a == models.TradeLF) {
+			direction = a
+		} else if c, ok := parseCategory(a); category == "" && a != "" && ok {
+			category = c
+		} else {
+			break
+		}
+		args = args[1:]
+	}
+	return direction, category, args
+}
+
+// filterTrades keeps the trades with the direction and category; empty
+// filters match every trade
+func filterTrades(trades []models.TradeData, direction, category string) []models.TradeData {
+	var ret []models.TradeData
+	for _, t := range trades {
+		if direction != "" && t.Direction != direction {
+			continue
+		}
+		if category != "" && t.Category != category {
+			continue
+		}
+		ret = append(ret, t)
+	}
+	return ret
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yiping-allison/isabelle/models"
+)
+
+func TestLinesMatch(t *testing.T) {
+	tests := map[string]struct {
+		want []models.TradeLine
+		have []models.TradeLine
+		out  bool
+	}{
+		"same catalog item": {
+			want: []models.TradeLine{{Item: "nook miles ticket", Quantity: 2, ItemID: 1}},
+			have: []models.TradeLine{{Item: "gold nugget", Quantity: 1, ItemID: 2}, {Item: "nook miles ticket", Quantity: 5, ItemID: 1}},
+			out:  true,
+		},
+		"different catalog items": {
+			want: []models.TradeLine{{Item: "ironwood dresser", Quantity: 1, ItemID: 3}},
+			have: []models.TradeLine{{Item: "ironwood kitchenette", Quantity: 1, ItemID: 4}},
+			out:  false,
+		},
+		"free text": {
+			want: []models.TradeLine{{Item: "royal crown", Quantity: 1}},
+			have: []models.TradeLine{{Item: "Royal Crowns", Quantity: 2}},
+			out:  true,
+		},
+		"nothing wanted": {
+			have: []models.TradeLine{{Item: "royal crown", Quantity: 1}},
+			out:  false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := linesMatch(tc.want, tc.have); got != tc.out {
+				t.Errorf("linesMatch() got = %v; want %v", got, tc.out)
+			}
+		})
+	}
+}
+
+func TestParseTradeFilters(t *testing.T) {
+	tests := map[string]struct {
+		in        []string
+		direction string
+		category  string
+		rest      []string
+	}{
+		"no filters": {
+			in:   []string{"gold", "nugget"},
+			rest: []string{"gold", "nugget"},
+		},
+		"direction and category": {
+			in:        []string{"LF", "diy", "crown"},
+			direction: models.TradeLF,
+			category:  "diy",
+			rest:      []string{"crown"},
+		},
+		"singular category": {
+			in:       []string{"ticket"},
+			category: "tickets",
+			rest:     []string{},
+		},
+		"category before direction": {
+			in:        []string{"furniture", "ft"},
+			direction: models.TradeFT,
+			category:  "furniture",
+			rest:      []string{},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			direction, category, rest := parseTradeFilters(tc.in)
+			if direction != tc.direction || category != tc.category {
+				t.Fatalf("parseTradeFilters() got = %q, %q; want %q, %q", direction, category, tc.direction, tc.category)
+			}
+			if !reflect.DeepEqual(rest, tc.rest) {
+				t.Errorf("parseTradeFilters() rest = %v; want %v", rest, tc.rest)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MinRep allows moderators (or bot controllers) to set the server wide minimum
+// reputation needed to join each event type
+//
+// The command usage should look like: ?minrep celeste 5
+//
+// Using only ?minrep lists the current minimums
+func MinRep(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) == 1 {
+		var fields []*discordgo.MessageEmbedField
+		for _, mr := range cmdInfo.Service.MinRep.All() {
+			if t, ok := eventTypes[mr.EventType]; ok {
+				fields = append(fields, createFields(t.name, strconv.Itoa(mr.Min), true))
+			}
+		}
+		if len(fields) == 0 {
+			fields = append(fields, createFields("None", "No event type has a minimum reputation.", false))
+		}
+		msg := cmdInfo.createMsgEmbed("Minimum Reputation", listThumbURL, "Server wide minimums per event type", listColor, fields)
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	if !isAdmin(cmdInfo.Msg.Member.Roles, cmdInfo.AdminRole) {
+		// must be admin to change minimums
+		return
+	}
+	eventName := strings.ToLower(cmdInfo.CmdOps[1])
+	t, ok := eventTypes[eventName]
+	var min int
+	var err error
+	if len(cmdInfo.CmdOps) == 3 {
+		min, err = parseMinRep(cmdInfo.CmdOps[2])
+	}
+	if !ok || len(cmdInfo.CmdOps) != 3 || err != nil {
+		var types []string
+		for k := range eventTypes {
+			types = append(types, k)
+		}
+		sort.Strings(types)
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Set Minimum Reputation", errThumbURL, "Try checking your syntax.", errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"minrep celeste 5", true),
+				createFields("Event Types", strings.Join(types, ", "), false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	if err := cmdInfo.Service.MinRep.Set(eventName, min); err != nil {
+		return
+	}
+	msg := cmdInfo.createMsgEmbed(
+		"Minimum Reputation Updated", checkThumbURL, t.name, successColor,
+		format(
+			createFields("Minimum", strconv.Itoa(min), true),
+			createFields("Note", "Applies to events created from now on.", false),
+		))
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+	cmdInfo.logAction("Minimum Reputation",
+		createFields("Event Type", t.name, true),
+		createFields("Minimum", strconv.Itoa(min), true),
+	)
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
 )
 
 // Offer handles the offer capabilities to trade
@@ -15,6 +16,17 @@ func Offer(cmdInfo CommandInfo) {
 		// wrong arguments to command
 		return
 	}
+	switch strings.ToLower(cmdInfo.CmdOps[1]) {
+	case "accept":
+		acceptCounter(cmdInfo, cmdInfo.CmdOps[2])
+		return
+	case "withdraw":
+		cmdInfo.removeFromTrade(cmdInfo.CmdOps[2], cmdInfo.Msg.Author)
+		return
+	case "edit":
+		editOffer(cmdInfo)
+		return
+	}
 	id := cmdInfo.CmdOps[1]
 	user := cmdInfo.Msg.Author
 	// if user doesn't exist in rep database, create a new one
@@ -32,18 +44,35 @@ func Offer(cmdInfo CommandInfo) {
 		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
 		return
 	}
-	offer := strings.Join(cmdInfo.CmdOps[2:], " ")
-	offer = strings.Title(offer)
+	sealed := cmdInfo.Service.Trade.GetTrade(id).Sealed
+	if sealed && cmdInfo.Msg.GuildID != "" {
+		// error - sealed offers must be sent by DM; hide the offer if we can
+		cmdInfo.Ses.ChannelMessageDelete(cmdInfo.Msg.ChannelID, cmdInfo.Msg.ID)
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Sealed Trade", errThumbURL, "Trade ID: "+id,
+			errColor, format(
+				createFields("Suggestion", "Offers to this trade are only seen by the host. Send your offer to me by DM.", false),
+				createFields("EXAMPLE", cmdInfo.Prefix+"offer "+id+" 2x nmt", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	lines, ok := cmdInfo.offerLines("Error: Couldn't Add To Trade", cmdInfo.CmdOps[2:], cmdInfo.Prefix+"offer "+id+" 2x nmt + 50k bells")
+	if !ok {
+		return
+	}
+	offer := formatLines(lines, ", ")
 
 	// add offer to tracking
-	err := cmdInfo.Service.Trade.AddOffer(id, offer, user)
+	rep := cmdInfo.Service.Rep.GetRep(user.ID)
+	err := cmdInfo.Service.Trade.AddOffer(id, offer, lines, rep, user)
 	if err != nil {
 		// error - user already offered
 		msg := cmdInfo.createMsgEmbed(
 			"Error: Couldn't Add To Trade", errThumbURL, strings.Title(err.Error()),
 			errColor, format(
 				createFields("User", user.Mention(), true),
-				createFields("Suggestion", "You can remove your existing offer and try again.", false),
+				createFields("Suggestion", "You can change your existing offer with "+cmdInfo.Prefix+"offer edit "+id+" your new offer", false),
 			))
 		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
 		return
@@ -60,7 +89,10 @@ func Offer(cmdInfo CommandInfo) {
 	cmdInfo.Service.User.AddOffer(id, user, expire)
 	// get original trade host info
 	host := cmdInfo.Service.Trade.GetHost(id)
-	rep := cmdInfo.Service.Rep.GetRep(user.ID)
+	if sealed {
+		cmdInfo.sealedOffer(id, offer, rep)
+		return
+	}
 	// print success msg
 	embed := cmdInfo.createMsgEmbed(
 		"Successfully Added Offer!", checkThumbURL, "Trade ID: "+id,
@@ -74,5 +106,127 @@ func Offer(cmdInfo CommandInfo) {
 		Content: host.Mention() + ": A new person has offered to your trade!",
 		Embed:   embed,
 	}
-	cmdInfo.Ses.ChannelMessageSendComplex(cmdInfo.BotChID, cplx)
+	cmdInfo.Ses.ChannelMessageSendComplex(cmdInfo.PublicChID, cplx)
+}
+
+// editOffer replaces an open offer and lets the host know what changed; the
+// host can still see the earlier offers
+//
+// The command usage should look like: ?offer edit 1234 3x nmt
+func editOffer(cmdInfo CommandInfo) {
+	example := cmdInfo.Prefix + "offer edit 1234 3x nmt + 50k bells"
+	if len(cmdInfo.CmdOps) < 4 {
+		cmdInfo.offerReplyError("Error: Couldn't Edit Offer", "Try checking your syntax.")
+		return
+	}
+	id, user := cmdInfo.CmdOps[2], cmdInfo.Msg.Author
+	t := cmdInfo.Service.Trade.GetTrade(id)
+	if t.DiscordUser == nil {
+		cmdInfo.offerReplyError("Error: Couldn't Edit Offer", "Trade ID "+id+" does not exist.")
+		return
+	}
+	if t.Sealed && cmdInfo.Msg.GuildID != "" {
+		// keep the new offer of a sealed trade private too
+		cmdInfo.Ses.ChannelMessageDelete(cmdInfo.Msg.ChannelID, cmdInfo.Msg.ID)
+		cmdInfo.offerReplyError("Error: Sealed Trade", "Offers to this trade are only seen by the host. Send your new offer to me by DM.")
+		return
+	}
+	lines, ok := cmdInfo.offerLines("Error: Couldn't Edit Offer", cmdInfo.CmdOps[3:], example)
+	if !ok {
+		return
+	}
+	o, err := cmdInfo.Service.Trade.EditOffer(id, user.ID, formatLines(lines, ", "), lines)
+	if err != nil {
+		cmdInfo.offerReplyError("Error: Couldn't Edit Offer", strings.Title(err.Error()))
+		return
+	}
+	previous := o.History[len(o.History)-1].Offer
+	fields := format(
+		createFields("Offerer", user.Mention(), true),
+		createFields("Revision", strconv.Itoa(len(o.History)+1), true),
+		createFields("Previous Offer", previous, false),
+		createFields("New Offer", o.Offer, false),
+	)
+	if t.Sealed {
+		embed := cmdInfo.createMsgEmbed(
+			"Successfully Edited Sealed Offer!", checkThumbURL, "Trade ID: "+id, successColor,
+			format(
+				createFields("Offer Item", o.Offer, true),
+				createFields("Suggestion", "Only the host can see your offer.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, embed)
+		cmdInfo.sendDM(t.DiscordUser.ID, cmdInfo.createMsgEmbed("Sealed Offer Edited", tradeThumbURL, "Trade ID: "+id, tradeColor, fields))
+		notice := cmdInfo.createMsgEmbed(
+			"Sealed Offer Edited", tradeThumbURL, "Trade ID: "+id, tradeColor,
+			format(
+				createFields("Offers", "An offer was revised. Only the host can see it.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendComplex(cmdInfo.PublicChID, &discordgo.MessageSend{
+			Content: t.DiscordUser.Mention() + ": An offer to your trade was edited! Check your DMs.",
+			Embed:   notice,
+		})
+		return
+	}
+	embed := cmdInfo.createMsgEmbed("Successfully Edited Offer!", checkThumbURL, "Trade ID: "+id, successColor, fields)
+	cmdInfo.Ses.ChannelMessageSendComplex(cmdInfo.PublicChID, &discordgo.MessageSend{
+		Content: t.DiscordUser.Mention() + ": An offer to your trade was edited!",
+		Embed:   embed,
+	})
+}
+
+// offerLines parses and resolves the lines of an offer; errors are printed
+// with the given title and example
+func (c CommandInfo) offerLines(title string, args []string, example string) ([]models.TradeLine, bool) {
+	lines, err := parseLines(strings.Join(args, " "))
+	if err != nil {
+		msg := c.createMsgEmbed(
+			title, errThumbURL, strings.Title(err.Error()),
+			errColor, format(
+				createFields("EXAMPLE", example, false),
+			))
+		c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+		return nil, false
+	}
+	lines, item, suggestions := c.resolveLines(lines)
+	if item != "" {
+		c.suggestItems(title, item, suggestions)
+		return nil, false
+	}
+	return lines, true
+}
+
+// sealedOffer confirms a sealed offer to the offerer, shows it to the host
+// by DM and only announces the amount of offers publicly
+func (c CommandInfo) sealedOffer(tradeID, offer string, rep int) {
+	user := c.Msg.Author
+	t := c.Service.Trade.GetTrade(tradeID)
+	embed := c.createMsgEmbed(
+		"Successfully Added Sealed Offer!", checkThumbURL, "Trade ID: "+tradeID,
+		successColor, format(
+			createFields("Offer Item", offer, true),
+			createFields("Suggestion", "Only the host can see your offer. Please Wait Until Trader Makes a Decision. Thank you!", false),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, embed)
+
+	dm := c.createMsgEmbed(
+		"New Sealed Offer", tradeThumbURL, "Trade ID: "+tradeID,
+		tradeColor, format(
+			createFields("Offerer", user.Mention(), true),
+			createFields("Reputation", strconv.Itoa(rep), true),
+			createFields("Offer Item", offer, false),
+			createFields("Accept", c.Prefix+"trade accept "+tradeID+" "+user.Mention(), false),
+			createFields("Counter", c.Prefix+"counter "+tradeID+" "+user.Mention()+" your counter-offer", false),
+			createFields("Decline", c.Prefix+"decline "+tradeID+" "+user.Mention(), false),
+		))
+	c.sendDM(t.DiscordUser.ID, dm)
+
+	notice := c.createMsgEmbed(
+		"New Sealed Offer", tradeThumbURL, "Trade ID: "+tradeID,
+		tradeColor, format(
+			createFields("Offers", "A new offer was received ("+strconv.Itoa(openOffers(t))+" total)", false),
+		))
+	c.Ses.ChannelMessageSendComplex(c.PublicChID, &discordgo.MessageSend{
+		Content: t.DiscordUser.Mention() + ": A new person has offered to your trade! Check your DMs.",
+		Embed:   notice,
+	})
 }
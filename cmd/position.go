@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
+)
+
+// Position tells a user their position in every queue they're in along with
+// an estimated wait
+//
+// The estimate is also sent to the user's DMs where it's refreshed whenever
+// they move up
+//
+// The command usage should look like: ?position [eventID]
+func Position(cmdInfo CommandInfo) {
+	user := cmdInfo.Msg.Author
+	events := cmdInfo.Service.Event.All()
+	if len(cmdInfo.CmdOps) > 1 {
+		if !cmdInfo.Service.Event.EventExists(cmdInfo.CmdOps[1]) {
+			msg := cmdInfo.createMsgEmbed(
+				"Error: Event Not Found", errThumbURL, "Event ID: "+cmdInfo.CmdOps[1], errColor,
+				format(
+					createFields("EXAMPLE", cmdInfo.Prefix+"position 1234", true),
+					createFields("EXAMPLE", cmdInfo.Prefix+"position", true),
+				))
+			cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+			return
+		}
+		events = []models.EventData{cmdInfo.Service.Event.GetEvent(cmdInfo.CmdOps[1])}
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	for _, e := range events {
+		text, pos := positionText(e, user.ID, time.Now())
+		if pos == 0 && text == "" {
+			continue
+		}
+		fields = append(fields, createFields(e.ID+" - "+e.Name, text, false))
+		if pos > 0 {
+			cmdInfo.watchPosition(e, pos)
+		}
+	}
+	if len(fields) == 0 {
+		msg := cmdInfo.createMsgEmbed(
+			"Not In Any Queue", errThumbURL, user.Mention(), errColor,
+			format(
+				createFields("Suggestion", "Use "+cmdInfo.Prefix+"events to find an event to join.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	fields = append(fields, createFields("Note", "Your position is also in your DMs and will update as you move up.", false))
+	msg := cmdInfo.createMsgEmbed("Queue Positions", queueThumbURL, user.Mention(), eventColor, fields)
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+}
+
+// watchPosition sends the user's position in an event to their DMs and
+// remembers the message so it can be refreshed
+func (c CommandInfo) watchPosition(e models.EventData, pos int) {
+	userID := c.Msg.Author.ID
+	ch, err := c.Ses.UserChannelCreate(userID)
+	if err != nil {
+		return
+	}
+	m, err := c.Ses.ChannelMessageSendEmbed(ch.ID, c.positionEmbed(e, userID))
+	if err != nil {
+		return
+	}
+	c.Service.Event.Watch(e.ID, userID, models.Watcher{
+		ChannelID: ch.ID,
+		MessageID: m.ID,
+		Position:  pos,
+	})
+}
+
+// refreshPositions updates the DM of every watching user who moved up in
+// an event's queue
+func (c CommandInfo) refreshPositions(eventID string) {
+	e := c.Service.Event.GetEvent(eventID)
+	for userID, w := range e.Watchers {
+		_, pos := positionText(e, userID, time.Now())
+		if pos > 0 && pos >= w.Position {
+			// didn't move up
+			continue
+		}
+		c.Ses.ChannelMessageEditEmbed(w.ChannelID, w.MessageID, c.positionEmbed(e, userID))
+		if pos == 0 {
+			c.Service.Event.Unwatch(eventID, userID)
+			continue
+		}
+		w.Position = pos
+		c.Service.Event.Watch(eventID, userID, w)
+	}
+}
+
+// positionEmbed builds the DM embed of a user's position in an event
+func (c CommandInfo) positionEmbed(e models.EventData, userID string) *discordgo.MessageEmbed {
+	text, _ := positionText(e, userID, time.Now())
+	if text == "" {
+		text = "You're no longer waiting in this queue."
+	}
+	msg := c.createMsgEmbed(
+		"Queue Position: "+e.Name, queueThumbURL, "Queue ID: "+e.ID, eventColor,
+		format(
+			createFields("Hosted By", e.DiscordUser.Mention(), true),
+			createFields("Status", text, false),
+		))
+	msg.Timestamp = time.Now().Format(time.RFC3339)
+	return msg
+}
+
+// positionText describes a user's place in an event and returns their overall
+// position in line (queue first, then waitlist)
+//
+// The position is 0 if the user isn't waiting in line; the text is empty if the
+// user isn't part of the event at all
+func positionText(e models.EventData, userID string, now time.Time) (string, int) {
+	since := e.Start
+	if e.Lottery {
+		since = since.Add(e.Window)
+	}
+	for i, u := range e.Queue {
+		if u.DiscordUser.ID == userID {
+			pos := i + 1
+			return "Queue Position: " + strconv.Itoa(pos) + "\n" + waitText(pos, since, e.Departures, now), pos
+		}
+	}
+	for i, u := range e.Waitlist {
+		if u.DiscordUser.ID == userID {
+			pos := len(e.Queue) + i + 1
+			return "Waitlist Position: " + strconv.Itoa(i+1) + " (" + strconv.Itoa(pos) + " in line)\n" +
+				waitText(pos, since, e.Departures, now), pos
+		}
+	}
+	if e.Lottery && !e.Drawn && containsQueueUser(userID, e.Entries) {
+		return "Entered the lottery; the queue order is drawn in " + formatDuration(since.Sub(now)) + ".", 0
+	}
+	return "", 0
+}
+
+// waitText prints the estimated wait for a position in line
+func waitText(pos int, since time.Time, departures []time.Time, now time.Time) string {
+	wait, ok := estimateWait(pos, since, departures, now)
+	if !ok {
+		return "Estimated Wait: not enough queue movement yet"
+	}
+	return "Estimated Wait: about " + formatDuration(wait)
+}
+
+// estimateWait estimates how long a position in line has to wait based on how
+// quickly the queue has been shrinking since the queue opened
+//
+// It returns false if nobody has left the queue yet
+func estimateWait(pos int, since time.Time, departures []time.Time, now time.Time) (time.Duration, bool) {
+	var n int
+	for _, d := range departures {
+		if !d.Before(since) {
+			n++
+		}
+	}
+	if n == 0 || !now.After(since) {
+		return 0, false
+	}
+	avg := now.Sub(since) / time.Duration(n)
+	return avg * time.Duration(pos), true
+}
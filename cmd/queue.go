@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
 )
 
 // Queue handles the queue-ing system; queue ids are retrieved from
@@ -17,7 +18,7 @@ func Queue(cmdInfo CommandInfo) {
 			errColor, format(
 				createFields("EXAMPLE", cmdInfo.Prefix+"queue 1234", true),
 			))
-		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		cmdInfo.replyError(msg)
 		return
 	}
 
@@ -28,11 +29,30 @@ func Queue(cmdInfo CommandInfo) {
 			errColor, format(
 				createFields("EXAMPLE", cmdInfo.Prefix+"queue 1234", true),
 			))
-		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		cmdInfo.replyError(msg)
 		return
 	}
 
 	user := cmdInfo.Msg.Author
+	e := cmdInfo.Service.Event.GetEvent(cmdInfo.CmdOps[1])
+	if cmdInfo.blockedBy(e, user.ID) {
+		// Error - host or a co-host blocked this user from their events
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Add To Queue", errThumbURL, "The host or a co-host has blocked you from their events.",
+			errColor, format(
+				createFields("User", user.Mention(), true),
+				createFields("Suggestion", "If you think this is a mistake, please PM the mods.", false),
+			))
+		cmdInfo.replyError(msg)
+		return
+	}
+
+	if !e.Open {
+		// Upcoming event - remind the user when the queue opens instead
+		cmdInfo.subscribe(cmdInfo.CmdOps[1], e)
+		return
+	}
+
 	if cmdInfo.Service.User.LimitQueue(user) {
 		// Error - max queue reached
 		msg := cmdInfo.createMsgEmbed(
@@ -40,12 +60,43 @@ func Queue(cmdInfo CommandInfo) {
 			errColor, format(
 				createFields("Suggestion", "Remove a queue before trying to add another queue.", false),
 			))
-		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		cmdInfo.replyError(msg)
+		return
+	}
+
+	// if user doesn't exist in rep database, create a new one
+	if !cmdInfo.Service.Rep.Exists(user.ID) {
+		cmdInfo.newRep(user.ID)
+	}
+	// retrieve rep
+	rep := cmdInfo.Service.Rep.GetRep(user.ID)
+
+	if score, n := cmdInfo.Service.Attendance.Reliability(user.ID); n > 0 && score < e.MinReliability {
+		// Error - too many no-shows for this host
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Add To Queue", errThumbURL, "This event requires a reliability of at least "+
+				strconv.Itoa(e.MinReliability)+"% and yours is "+strconv.Itoa(score)+"%.",
+			errColor, format(
+				createFields("User", user.Mention(), true),
+				createFields("Suggestion", "Reliability goes up by confirming when you're called.", false),
+			))
+		cmdInfo.replyError(msg)
+		return
+	}
+
+	if e.Lottery && !e.Drawn {
+		// Lottery entry window - record an entry instead
+		cmdInfo.enter(cmdInfo.CmdOps[1], rep)
 		return
 	}
 
 	// Add user to queue
-	host, err := cmdInfo.Service.Event.AddToQueue(user, cmdInfo.CmdOps[1])
+	_, err := cmdInfo.Service.Event.AddToQueue(user, rep, cmdInfo.CmdOps[1])
+	if err != nil && err.Error() == models.ErrQueueFull && e.WaitlistLimit > 0 {
+		// Queue is full - try the waitlist instead
+		cmdInfo.waitlist(cmdInfo.CmdOps[1], rep)
+		return
+	}
 	if err != nil {
 		// Check error
 		msg := cmdInfo.createMsgEmbed(
@@ -54,7 +105,7 @@ func Queue(cmdInfo CommandInfo) {
 				createFields("User", user.Mention(), true),
 				createFields("EXAMPLE", cmdInfo.Prefix+"queue 1234", true),
 			))
-		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		cmdInfo.replyError(msg)
 		return
 	}
 
@@ -66,23 +117,122 @@ func Queue(cmdInfo CommandInfo) {
 	// Add queue to user tracking
 	cmdInfo.Service.User.AddQueue(cmdInfo.CmdOps[1], user, cmdInfo.Service.Event.GetExpiration(cmdInfo.CmdOps[1]))
 
-	// if user doesn't exist in rep database, create a new one
-	if !cmdInfo.Service.Rep.Exists(user.ID) {
-		cmdInfo.newRep(user.ID)
-	}
-	// retrieve rep
-	rep := cmdInfo.Service.Rep.GetRep(user.ID)
-
 	embed := cmdInfo.createMsgEmbed(
 		"Successfully Added to Queue!", checkThumbURL, "Queue ID: "+cmdInfo.CmdOps[1],
 		successColor, format(
 			createFields("User", user.Mention(), true),
 			createFields("Reputation", strconv.Itoa(rep), true),
+			createFields("Reliability", cmdInfo.reliabilityText(user.ID), true),
 			createFields("Please Wait Until You're Pinged or Messaged!", "Thank you!", false),
 		))
 	cplx := &discordgo.MessageSend{
-		Content: host.Mention() + ": A new person has joined your queue!",
+		Content: cmdInfo.hostMentions(cmdInfo.CmdOps[1]) + ": A new person has joined your queue!",
 		Embed:   embed,
 	}
 	cmdInfo.Ses.ChannelMessageSendComplex(cmdInfo.BotChID, cplx)
 }
+
+// blockedBy returns true if the host or any co-host of an event blocked the
+// user from their events
+func (c CommandInfo) blockedBy(e models.EventData, userID string) bool {
+	if e.DiscordUser != nil && c.Service.Block.Blocked(e.DiscordUser.ID, userID) {
+		return true
+	}
+	for _, u := range e.CoHosts {
+		if c.Service.Block.Blocked(u.ID, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribe adds the user to the reminder list of an upcoming event
+func (c CommandInfo) subscribe(eventID string, e models.EventData) {
+	user := c.Msg.Author
+	loc := c.Service.Profile.Location(user.ID)
+	if err := c.Service.Event.Subscribe(user, eventID); err != nil {
+		msg := c.createMsgEmbed(
+			"Error: Couldn't Subscribe To Event", errThumbURL, strings.Title(err.Error()),
+			errColor, format(
+				createFields("User", user.Mention(), true),
+				createFields("Queue Opens", formatTime(e.Start, loc), true),
+			))
+		c.replyError(msg)
+		return
+	}
+	msg := c.createMsgEmbed(
+		"Subscribed to Upcoming Event!", checkThumbURL, "Queue ID: "+eventID,
+		successColor, format(
+			createFields("User", user.Mention(), true),
+			createFields("Queue Opens", formatTime(e.Start, loc), true),
+			createFields("Note", "You will be messaged shortly before the queue opens.", false),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// waitlist adds the user to the waitlist of a full queue
+func (c CommandInfo) waitlist(eventID string, rep int) {
+	user := c.Msg.Author
+	_, pos, err := c.Service.Event.AddToWaitlist(user, rep, eventID)
+	if err != nil {
+		msg := c.createMsgEmbed(
+			"Error: Couldn't Add To Waitlist", errThumbURL, strings.Title(err.Error()),
+			errColor, format(
+				createFields("User", user.Mention(), true),
+				createFields("EXAMPLE", c.Prefix+"queue 1234", true),
+			))
+		c.replyError(msg)
+		return
+	}
+
+	// waitlisted users are tracked like queues so unregister and close clean them up
+	if !c.Service.User.UserExists(user) {
+		c.Service.User.AddUser(user)
+	}
+	c.Service.User.AddQueue(eventID, user, c.Service.Event.GetExpiration(eventID))
+
+	embed := c.createMsgEmbed(
+		"Queue Full - Added to Waitlist!", checkThumbURL, "Queue ID: "+eventID,
+		successColor, format(
+			createFields("User", user.Mention(), true),
+			createFields("Waitlist Position", strconv.Itoa(pos), true),
+			createFields("Note", "You will be messaged if a spot in the queue opens up.", false),
+		))
+	cplx := &discordgo.MessageSend{
+		Content: c.hostMentions(eventID) + ": A new person has joined your waitlist!",
+		Embed:   embed,
+	}
+	c.Ses.ChannelMessageSendComplex(c.BotChID, cplx)
+}
+
+// notifyPromoted lets users who moved from the waitlist into the queue know
+func (c CommandInfo) notifyPromoted(eventID string, users []*discordgo.User) {
+	if len(users) == 0 {
+		return
+	}
+	hosts := c.hostMentions(eventID)
+	for _, u := range users {
+		msg := c.createMsgEmbed(
+			"You're Off the Waitlist!", checkThumbURL, "Queue ID: "+eventID,
+			successColor, format(
+				createFields("Hosted By", hosts, true),
+				createFields("Note", "A spot opened up and you're now in the queue. Please wait until you're pinged or messaged!", false),
+			))
+		c.sendDM(u.ID, msg)
+		cplx := &discordgo.MessageSend{
+			Content: hosts + ": " + u.Mention() + " moved from the waitlist into your queue!",
+			Embed:   msg,
+		}
+		c.Ses.ChannelMessageSendComplex(c.BotChID, cplx)
+	}
+}
+
+// hostMentions mentions the host and every co-host of an event
+func (c CommandInfo) hostMentions(eventID string) string {
+	e := c.Service.Event.GetEvent(eventID)
+	mentions := []string{e.DiscordUser.Mention()}
+	for _, u := range e.CoHosts {
+		mentions = append(mentions, u.Mention())
+	}
+	return strings.Join(mentions, " ")
+}
@@ -0,0 +1,24 @@
+package cmd
+
+// JoinEmoji is added to every event listing; reacting with it joins the
+// queue and removing the reaction unregisters
+const JoinEmoji = "✅"
+
+// React joins or leaves an event queue when a user reacts to its listing
+//
+// cmdInfo.Msg only carries the reacting user and cmdInfo.Reacted must be set
+// so errors are sent by DM
+func React(cmdInfo CommandInfo, eventID string, join bool) {
+	if join {
+		cmdInfo.CmdOps = []string{"queue", eventID}
+		Queue(cmdInfo)
+		return
+	}
+	e := cmdInfo.Service.Event.GetEvent(eventID)
+	userID := cmdInfo.Msg.Author.ID
+	if !containsQueueUser(userID, e.Queue) && !containsQueueUser(userID, e.Waitlist) && !containsQueueUser(userID, e.Entries) {
+		// joining failed or the user already left
+		return
+	}
+	cmdInfo.removeFromEvent(eventID, cmdInfo.Msg.Author)
+}
@@ -4,26 +4,46 @@ import (
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
 )
 
 // Rep will allow server members to update reputation points
 // on another member
+//
+// Applications must come from a visit or trade the host confirmed.
+//
+// The command usage should look like: ?rep @user 1234 great trade
 func Rep(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) < 3 {
+		cmdInfo.repError("Not enough arguments supplied.")
+		return
+	}
+	user := cmdInfo.Msg.Author
 	userID := stripPing(cmdInfo.CmdOps[1])
+	i, err := cmdInfo.Service.Interaction.Find(cmdInfo.CmdOps[2], user.ID, userID)
+	if err == nil {
+		err = cmdInfo.Service.Interaction.Nominate(i, user.ID)
+	}
+	if err != nil {
+		cmdInfo.repError(strings.Title(err.Error()))
+		return
+	}
 	if !cmdInfo.Service.Rep.Exists(userID) {
 		// if the user doesn't exist in rep database, create a new one
 		cmdInfo.newRep(userID)
 	}
 	// generate random 4 digit ID for acception event
 	id := generateID(1000, 9999)
-	cmdInfo.Service.Rep.AddRep(userID, id)
-	userMsg := strings.Join(cmdInfo.CmdOps[2:], " ")
+	source := interactionSource(i)
+	cmdInfo.Service.Rep.AddRep(userID, id, source)
+	userMsg := strings.Join(cmdInfo.CmdOps[3:], " ")
 	// print rep msg
 	msg := cmdInfo.createMsgEmbed(
 		"Reputation Application", thumbThumbURL, "App ID: "+id,
 		appColor, format(
 			createFields("Nominee", mentionUser(userID), true),
 			createFields("Message", userMsg, true),
+			createFields("From", source+"\nFiled by "+user.Mention(), false),
 			createFields("Note", "The mods will try to process this app ASAP. Thank you for submitting!", false),
 		))
 	cplx := &discordgo.MessageSend{
@@ -34,6 +54,59 @@ func Rep(cmdInfo CommandInfo) {
 	cmdInfo.Ses.ChannelMessageSend(cmdInfo.BotChID, "Application Submitted!")
 }
 
+// repError prints why a rep application couldn't be filed
+func (c CommandInfo) repError(reason string) {
+	msg := c.createMsgEmbed(
+		"Error: Couldn't Submit Application", errThumbURL, reason, errColor,
+		format(
+			createFields("EXAMPLE", c.Prefix+"rep @user 1234 great trade", true),
+			createFields("Note", "Applications are unlocked when a host confirms a visit ("+c.Prefix+
+				"host visited 1234 @user) or a trade ("+c.Prefix+"trade complete 1234 @user).", false),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// confirmInteraction records a confirmed visit or trade and invites both
+// sides to file a rep application
+func (c CommandInfo) confirmInteraction(i *models.Interaction) {
+	if err := c.Service.Interaction.Record(i); err != nil {
+		msg := c.createMsgEmbed(
+			"Error: Couldn't Confirm", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("ID", i.ListingID, true),
+				createFields("User", mentionUser(i.UserID), true),
+			))
+		c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+		return
+	}
+	source := interactionSource(i)
+	msg := c.createMsgEmbed(
+		"Interaction Confirmed!", checkThumbURL, source, successColor,
+		format(
+			createFields("Host", mentionUser(i.HostID), true),
+			createFields("User", mentionUser(i.UserID), true),
+			createFields("Note", "Both of you were messaged about leaving a rep application.", false),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+	for _, pair := range [][2]string{{i.HostID, i.UserID}, {i.UserID, i.HostID}} {
+		dm := c.createMsgEmbed(
+			"Leave a Rep Application?", thumbThumbURL, source, appColor,
+			format(
+				createFields("How", c.Prefix+"rep "+mentionUser(pair[1])+" "+i.ListingID+" your message", false),
+				createFields("Note", "Applications can be filed in the bot channel within 7 days.", false),
+			))
+		c.sendDM(pair[0], dm)
+	}
+}
+
+// interactionSource describes where an interaction came from for mods
+func interactionSource(i *models.Interaction) string {
+	if i.Kind == models.InteractionTrade {
+		return "Trade " + i.ListingID + " (" + strings.Title(i.Name) + ") hosted by " + mentionUser(i.HostID)
+	}
+	return "Visit to event " + i.ListingID + " (" + i.Name + ") hosted by " + mentionUser(i.HostID)
+}
+
 // mentionUser is a helper func which mentions a user by ID
 func mentionUser(user string) string {
 	return "<@!" + user + ">"
@@ -46,8 +119,11 @@ func mentionRole(role string) string {
 
 // stripPing is a helper func which turns discord pings into a regular user id
 // string
+//
+// Both nickname (<@!id>) and regular (<@id>) pings are accepted
 func stripPing(ping string) string {
-	id := strings.TrimPrefix(ping, "<@!")
+	id := strings.TrimPrefix(ping, "<@")
+	id = strings.TrimPrefix(id, "!")
 	id = strings.TrimSuffix(id, ">")
 	return id
 }
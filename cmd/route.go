@@ -0,0 +1,29 @@
+package cmd
+
+// Route is where the listings of an event type or trade category are posted
+// and which role is pinged when one is posted
+type Route struct {
+	// ID of the listing channel; empty means the default listing channel
+	ChannelID string `json:"channelID"`
+
+	// Optional ID of the role to ping
+	RoleID string `json:"roleID"`
+}
+
+// route returns the route of an event type or trade category with the
+// default listing channel filled in
+func (c CommandInfo) route(routes map[string]Route, key string) Route {
+	r := routes[key]
+	if r.ChannelID == "" {
+		r.ChannelID = c.ListingID
+	}
+	return r
+}
+
+// rolePing mentions the role of a route; it's empty if the route has no role
+func (r Route) rolePing() string {
+	if r.RoleID == "" {
+		return ""
+	}
+	return mentionRole(r.RoleID)
+}
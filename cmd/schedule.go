@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// reminderLead is how long before an upcoming event opens that
+	// subscribers are reminded
+	reminderLead = 15 * time.Minute
+)
+
+// Schedule runs all timed bot tasks such as reminding subscribers, opening
+// scheduled events, skipping no-shows, posting repeating events and ending
+// auctions
+//
+// cmdInfo does not carry a message since this isn't triggered by a user;
+// this should only be called in the goroutine in main (ticker)
+func Schedule(cmdInfo CommandInfo) {
+	cmdInfo.remindEvents()
+	cmdInfo.openEvents()
+	cmdInfo.drawLotteries()
+	cmdInfo.skipNoShows()
+	cmdInfo.repeatEvents()
+	cmdInfo.endAuctions()
+}
+
+// remindEvents messages subscribers of events that open soon
+func (c CommandInfo) remindEvents() {
+	for _, id := range c.Service.Event.Remind(reminderLead) {
+		e := c.Service.Event.GetEvent(id)
+		for _, u := range e.Subscribers {
+			loc := c.Service.Profile.Location(u.ID)
+			msg := c.createMsgEmbed(
+				"Event Opening Soon: "+e.Name, e.Img, "Queue ID: "+id,
+				eventColor, format(
+					createFields("Hosted By", e.DiscordUser.Mention(), true),
+					createFields("Queue Opens", formatTime(e.Start, loc), true),
+					createFields("Join", c.Prefix+"queue "+id, false),
+				))
+			c.sendDM(u.ID, msg)
+		}
+	}
+}
+
+// openEvents opens the queues of scheduled events whose start time passed
+func (c CommandInfo) openEvents() {
+	for _, id := range c.Service.Event.OpenDue() {
+		e := c.Service.Event.GetEvent(id)
+		c.updateEvent(id)
+		content := c.hostMentions(id) + ": Your event is now open!"
+		for _, u := range e.Subscribers {
+			content += " " + u.Mention()
+		}
+		msg := c.createMsgEmbed(
+			"Queue Now Open: "+e.Name, queueThumbURL, "Queue ID: "+id,
+			eventColor, format(
+				createFields("Join", c.Prefix+"queue "+id, false),
+			))
+		c.Ses.ChannelMessageSendComplex(c.BotChID, &discordgo.MessageSend{
+			Content: content,
+			Embed:   msg,
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
+)
+
+// Subscribe lets users get a DM when a matching event or trade is listed
+//
+// The command usage should look like:
+//
+// ?subscribe event celeste
+//
+// ?subscribe trade "gold nugget"
+//
+// ?subscribe quiet 22:00 08:00
+func Subscribe(cmdInfo CommandInfo) {
+	user := cmdInfo.Msg.Author
+	if len(cmdInfo.CmdOps) == 1 {
+		cmdInfo.printSubscriptions()
+		return
+	}
+	kind := strings.ToLower(cmdInfo.CmdOps[1])
+	if kind == "quiet" {
+		setQuietHours(cmdInfo)
+		return
+	}
+	keyword := subKeyword(cmdInfo.CmdOps[2:])
+	if kind == models.SubEvent {
+		if _, ok := eventTypes[keyword]; !ok {
+			cmdInfo.subscribeSyntaxError("Unknown event type: " + keyword)
+			return
+		}
+	}
+	if err := cmdInfo.Service.Subscription.Subscribe(user.ID, kind, keyword); err != nil {
+		cmdInfo.subscribeSyntaxError(strings.Title(err.Error()))
+		return
+	}
+	msg := cmdInfo.createMsgEmbed(
+		"Subscribed!", checkThumbURL, "You'll be messaged when a matching "+kind+" is listed.", successColor,
+		format(
+			createFields("User", user.Mention(), true),
+			createFields(strings.Title(kind), keyword, true),
+		))
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+}
+
+// Unsubscribe removes listing subscriptions
+//
+// The command usage should look like: ?unsubscribe event celeste
+//
+// Using only ?unsubscribe removes every subscription
+func Unsubscribe(cmdInfo CommandInfo) {
+	user := cmdInfo.Msg.Author
+	var err error
+	if len(cmdInfo.CmdOps) == 1 {
+		err = cmdInfo.Service.Subscription.UnsubscribeAll(user.ID)
+	} else {
+		kind := strings.ToLower(cmdInfo.CmdOps[1])
+		err = cmdInfo.Service.Subscription.Unsubscribe(user.ID, kind, subKeyword(cmdInfo.CmdOps[2:]))
+	}
+	if err != nil {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Unsubscribe", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"unsubscribe event celeste", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"unsubscribe", true),
+				createFields("Suggestion", "Use "+cmdInfo.Prefix+"subscribe to see your subscriptions.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	msg := cmdInfo.createMsgEmbed("Unsubscribed", checkThumbURL, user.Mention(), successColor, nil)
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+}
+
+// subKeyword joins the words of a subscription keyword and drops quotes
+func subKeyword(words []string) string {
+	keyword := strings.Join(words, " ")
+	keyword = strings.Trim(keyword, "\"“” ")
+	return strings.ToLower(keyword)
+}
+
+// subscribeSyntaxError prints why a subscription failed with examples
+func (c CommandInfo) subscribeSyntaxError(reason string) {
+	msg := c.createMsgEmbed(
+		"Error: Couldn't Subscribe", errThumbURL, reason, errColor,
+		format(
+			createFields("EXAMPLE", c.Prefix+"subscribe event celeste", true),
+			createFields("EXAMPLE", c.Prefix+"subscribe trade \"gold nugget\"", true),
+			createFields("EXAMPLE", c.Prefix+"subscribe quiet 22:00 08:00", true),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// printSubscriptions prints the user's subscriptions and quiet hours
+func (c CommandInfo) printSubscriptions() {
+	user := c.Msg.Author
+	var events, trades []string
+	for _, s := range c.Service.Subscription.ByUser(user.ID) {
+		if s.Kind == models.SubEvent {
+			events = append(events, s.Keyword)
+		} else {
+			trades = append(trades, s.Keyword)
+		}
+	}
+	quiet := "Off"
+	if start, end := c.Service.Profile.QuietHours(user.ID); start != "" {
+		quiet = start + " - " + end + " " + c.Service.Profile.Location(user.ID).String()
+	}
+	fields := format(createFields("Quiet Hours", quiet, false))
+	if len(events) > 0 {
+		fields = append(fields, createFields("Events", strings.Join(events, ", "), false))
+	}
+	if len(trades) > 0 {
+		fields = append(fields, createFields("Trades", strings.Join(trades, ", "), false))
+	}
+	msg := c.createMsgEmbed("Your Subscriptions", listThumbURL, user.Mention(), listColor, fields)
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// setQuietHours sets or turns off the user's quiet hours
+func setQuietHours(cmdInfo CommandInfo) {
+	user := cmdInfo.Msg.Author
+	var start, end string
+	switch {
+	case len(cmdInfo.CmdOps) == 3 && strings.ToLower(cmdInfo.CmdOps[2]) == "off":
+	case len(cmdInfo.CmdOps) == 4:
+		start, end = cmdInfo.CmdOps[2], cmdInfo.CmdOps[3]
+	default:
+		cmdInfo.subscribeSyntaxError("Try checking your syntax.")
+		return
+	}
+	if err := cmdInfo.Service.Profile.SetQuietHours(user.ID, start, end); err != nil {
+		cmdInfo.subscribeSyntaxError(strings.Title(err.Error()))
+		return
+	}
+	quiet := "Off"
+	if start != "" {
+		quiet = start + " - " + end + " " + cmdInfo.Service.Profile.Location(user.ID).String()
+	}
+	msg := cmdInfo.createMsgEmbed(
+		"Quiet Hours Updated", checkThumbURL, quiet, successColor,
+		format(
+			createFields("Timezone", "Quiet hours use your timezone; set it with "+cmdInfo.Prefix+"timezone", false),
+		))
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+}
+
+// notifySubscribers messages every subscriber of a new listing unless it's
+// their own listing or their quiet hours
+//
+// Returns every subscriber that was considered so other listing alerts can
+// skip them
+func (c CommandInfo) notifySubscribers(subs []models.Subscription, hostID string, embed *discordgo.MessageEmbed) map[string]bool {
+	sent := make(map[string]bool)
+	for _, s := range subs {
+		if sent[s.UserID] {
+			continue
+		}
+		sent[s.UserID] = true
+		c.notifyUser(s.UserID, hostID, embed)
+	}
+	return sent
+}
+
+// notifyUser messages a user about a new listing unless it's their own
+// listing or their quiet hours
+func (c CommandInfo) notifyUser(userID, hostID string, embed *discordgo.MessageEmbed) {
+	if userID == hostID {
+		return
+	}
+	start, end := c.Service.Profile.QuietHours(userID)
+	if inQuietHours(time.Now(), start, end, c.Service.Profile.Location(userID)) {
+		return
+	}
+	c.sendDM(userID, embed)
+}
+
+// inQuietHours returns true if the time falls between the start and end of
+// quiet hours in the location; quiet hours may wrap past midnight
+func inQuietHours(now time.Time, start, end string, loc *time.Location) bool {
+	from, err := time.Parse("15:04", start)
+	if err != nil {
+		return false
+	}
+	to, err := time.Parse("15:04", end)
+	if err != nil {
+		return false
+	}
+	local := now.In(loc)
+	cur := local.Hour()*60 + local.Minute()
+	s := from.Hour()*60 + from.Minute()
+	e := to.Hour()*60 + to.Minute()
+	if s <= e {
+		return cur >= s && cur < e
+	}
+	return cur >= s || cur < e
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInQuietHours(t *testing.T) {
+	loc := time.FixedZone("UTC+9", 9*60*60)
+	tests := map[string]struct {
+		now        time.Time
+		start, end string
+		want       bool
+	}{
+		"no quiet hours": {
+			now:  time.Date(2026, 10, 19, 23, 0, 0, 0, loc),
+			want: false,
+		},
+		"inside same day": {
+			now:   time.Date(2026, 10, 19, 13, 30, 0, 0, loc),
+			start: "13:00",
+			end:   "14:00",
+			want:  true,
+		},
+		"end is exclusive": {
+			now:   time.Date(2026, 10, 19, 14, 0, 0, 0, loc),
+			start: "13:00",
+			end:   "14:00",
+			want:  false,
+		},
+		"before midnight when wrapping": {
+			now:   time.Date(2026, 10, 19, 23, 0, 0, 0, loc),
+			start: "22:00",
+			end:   "08:00",
+			want:  true,
+		},
+		"after midnight when wrapping": {
+			now:   time.Date(2026, 10, 20, 7, 59, 0, 0, loc),
+			start: "22:00",
+			end:   "08:00",
+			want:  true,
+		},
+		"daytime when wrapping": {
+			now:   time.Date(2026, 10, 20, 12, 0, 0, 0, loc),
+			start: "22:00",
+			end:   "08:00",
+			want:  false,
+		},
+		"read in the user's location": {
+			now:   time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC),
+			start: "22:00",
+			end:   "08:00",
+			want:  true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := inQuietHours(tc.now, tc.start, tc.end, loc); got != tc.want {
+				t.Errorf("inQuietHours() got = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
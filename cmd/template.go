@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
+)
+
+const (
+	// repeatLead is how long before a repeating event opens that its
+	// listing is posted
+	repeatLead = time.Hour
+)
+
+// weekdays maps day names to their time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// eventTemplate handles the template sub commands of ?event
+//
+// The command usage should look like:
+//
+// ?event save swap diy limit="5" msg="nightly diy swap"
+//
+// ?event run swap
+//
+// ?event repeat swap daily 21:00
+func eventTemplate(cmdInfo CommandInfo) {
+	switch strings.ToLower(cmdInfo.CmdOps[1]) {
+	case "save":
+		saveTemplate(cmdInfo)
+	case "run":
+		runTemplate(cmdInfo)
+	case "repeat":
+		repeatTemplate(cmdInfo)
+	case "templates":
+		listTemplates(cmdInfo)
+	case "delete":
+		deleteTemplate(cmdInfo)
+	}
+}
+
+// templateSyntaxError prints all template command examples
+func (c CommandInfo) templateSyntaxError() {
+	msg := c.createMsgEmbed(
+		"Error: Wrong Arguments", errThumbURL, "Try checking your syntax.", errColor,
+		format(
+			createFields("EXAMPLE", c.Prefix+"event save swap diy limit=\"5\" msg=\"nightly diy swap\"", false),
+			createFields("EXAMPLE", c.Prefix+"event run swap", true),
+			createFields("EXAMPLE", c.Prefix+"event repeat swap sun,wed 21:00", true),
+			createFields("EXAMPLE", c.Prefix+"event repeat swap off", true),
+			createFields("EXAMPLE", c.Prefix+"event templates", true),
+			createFields("EXAMPLE", c.Prefix+"event delete swap", true),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// templateNotFound prints an error for a template the host never saved
+func (c CommandInfo) templateNotFound(name string, err error) {
+	msg := c.createMsgEmbed(
+		"Error: "+strings.Title(err.Error()), errThumbURL, "Template: "+name, errColor,
+		format(
+			createFields("Suggestion", "Use "+c.Prefix+"event templates to see your templates.", false),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// saveTemplate saves an event command under a name so it can be run again
+func saveTemplate(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) < 5 {
+		cmdInfo.templateSyntaxError()
+		return
+	}
+	name := strings.ToLower(cmdInfo.CmdOps[2])
+	eventName := strings.ToLower(cmdInfo.CmdOps[3])
+	eType, ok := eventTypes[eventName]
+	args := parseArgs(strings.Join(cmdInfo.CmdOps[4:], " "))
+	event := eventFromArgs(args, eType.name, eType.img)
+	if !ok || event == nil {
+		cmdInfo.templateSyntaxError()
+		return
+	}
+	if args["start"] != "" {
+		cmdInfo.printEventError("Error: Couldn't Save Template", eventError{
+			msg: "Templates can't have a start time.",
+			fields: format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"event run swap start=\"in 1h\"", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event repeat swap daily 21:00", true),
+			),
+		})
+		return
+	}
+	// make sure the template would create a valid event
+	if _, err := cmdInfo.buildEvent(eventName, event, cmdInfo.Msg.Author); err != nil {
+		cmdInfo.printEventError("Error: Couldn't Save Template", err)
+		return
+	}
+	err := cmdInfo.Service.Template.Save(&models.Template{
+		HostID:    cmdInfo.Msg.Author.ID,
+		Name:      name,
+		EventType: eventName,
+		Args:      formatArgs(args),
+	})
+	if err != nil {
+		cmdInfo.printEventError("Error: Couldn't Save Template", errors.New(strings.Title(err.Error())))
+		return
+	}
+	msg := cmdInfo.createMsgEmbed(
+		"Template Saved!", checkThumbURL, "Template: "+name, successColor,
+		format(
+			createFields("Event", eType.name, true),
+			createFields("Run It", cmdInfo.Prefix+"event run "+name, true),
+			createFields("Repeat It", cmdInfo.Prefix+"event repeat "+name+" sun 21:00", true),
+		))
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+}
+
+// runTemplate posts an event from a saved template; extra key="value"
+// arguments replace the saved ones
+func runTemplate(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) < 3 {
+		cmdInfo.templateSyntaxError()
+		return
+	}
+	name := strings.ToLower(cmdInfo.CmdOps[2])
+	t, err := cmdInfo.Service.Template.Get(cmdInfo.Msg.Author.ID, name)
+	if err != nil {
+		cmdInfo.templateNotFound(name, err)
+		return
+	}
+	args := parseArgs(t.Args)
+	overrides := parseArgs(strings.Join(cmdInfo.CmdOps[3:], " "))
+	if args == nil || overrides == nil {
+		cmdInfo.templateSyntaxError()
+		return
+	}
+	for k, v := range overrides {
+		args[k] = v
+	}
+	if _, err := cmdInfo.launchTemplate(t, args, cmdInfo.Msg.Author); err != nil {
+		cmdInfo.printEventError("Error: Couldn't Create Event", err)
+		return
+	}
+	cmdInfo.Ses.ChannelMessageSend(cmdInfo.BotChID, "Listing Posted!")
+}
+
+// launchTemplate validates and posts an event from template arguments
+//
+// It returns the new event ID
+func (c CommandInfo) launchTemplate(t *models.Template, args map[string]string, host *discordgo.User) (string, error) {
+	eType, ok := eventTypes[t.EventType]
+	event := eventFromArgs(args, eType.name, eType.img)
+	if !ok || event == nil {
+		return "", eventError{msg: "The template is no longer valid; try saving it again."}
+	}
+	data, err := c.buildEvent(t.EventType, event, host)
+	if err != nil {
+		return "", err
+	}
+	return c.listEvent(data)
+}
+
+// repeatTemplate sets or stops the weekly pattern of a template
+func repeatTemplate(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) != 4 && len(cmdInfo.CmdOps) != 5 {
+		cmdInfo.templateSyntaxError()
+		return
+	}
+	host := cmdInfo.Msg.Author
+	name := strings.ToLower(cmdInfo.CmdOps[2])
+	if len(cmdInfo.CmdOps) == 4 {
+		if strings.ToLower(cmdInfo.CmdOps[3]) != "off" {
+			cmdInfo.templateSyntaxError()
+			return
+		}
+		if err := cmdInfo.Service.Template.SetRepeat(host.ID, name, 0, "", time.Time{}); err != nil {
+			cmdInfo.templateNotFound(name, err)
+			return
+		}
+		msg := cmdInfo.createMsgEmbed("Template No Longer Repeats", checkThumbURL, "Template: "+name, successColor, nil)
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+
+	days, err := parseDays(cmdInfo.CmdOps[3])
+	var clock time.Time
+	if err == nil {
+		clock, err = time.Parse("15:04", cmdInfo.CmdOps[4])
+	}
+	if err != nil {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Repeat Template", errThumbURL, "Days must be daily, weekdays, weekends or a list like sun,wed and the time must look like 21:00.", errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"event repeat swap daily 21:00", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"event repeat swap sun,wed 9:30", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	at := clock.Format("15:04")
+	loc := cmdInfo.Service.Profile.Location(host.ID)
+	next := nextRun(days, at, loc, time.Now())
+	if err := cmdInfo.Service.Template.SetRepeat(host.ID, name, days, at, next); err != nil {
+		cmdInfo.templateNotFound(name, err)
+		return
+	}
+	msg := cmdInfo.createMsgEmbed(
+		"Template Repeating!", checkThumbURL, "Template: "+name, successColor,
+		format(
+			createFields("Days", formatDays(days), true),
+			createFields("Queue Opens", at+" "+loc.String(), true),
+			createFields("Next", formatTime(next, loc), false),
+			createFields("Note", "The listing is posted an hour before the queue opens.", false),
+		))
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+}
+
+// listTemplates prints every template the host saved
+func listTemplates(cmdInfo CommandInfo) {
+	host := cmdInfo.Msg.Author
+	loc := cmdInfo.Service.Profile.Location(host.ID)
+	var fields []*discordgo.MessageEmbedField
+	for _, t := range cmdInfo.Service.Template.ByHost(host.ID) {
+		val := t.EventType + " " + t.Args
+		if t.Days != 0 {
+			val += "\nRepeats " + formatDays(t.Days) + " at " + t.OpensAt + " | Next: " + formatTime(t.NextRun, loc)
+		}
+		fields = append(fields, createFields(t.Name, truncate(val, 1024), false))
+	}
+	if len(fields) == 0 {
+		fields = append(fields, createFields("None", "Save one with "+cmdInfo.Prefix+"event save", false))
+	}
+	msg := cmdInfo.createMsgEmbed("Event Templates", listThumbURL, host.Mention(), listColor, fields)
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+}
+
+// deleteTemplate removes one of the host's templates
+func deleteTemplate(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) != 3 {
+		cmdInfo.templateSyntaxError()
+		return
+	}
+	name := strings.ToLower(cmdInfo.CmdOps[2])
+	if err := cmdInfo.Service.Template.Delete(cmdInfo.Msg.Author.ID, name); err != nil {
+		cmdInfo.templateNotFound(name, err)
+		return
+	}
+	msg := cmdInfo.createMsgEmbed("Template Deleted", checkThumbURL, "Template: "+name, successColor, nil)
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+}
+
+// repeatEvents posts the listings of repeating templates ahead of their
+// queue opening
+//
+// A run which couldn't be posted is retried every tick until its queue opens;
+// runs whose opening passed are skipped
+func (c CommandInfo) repeatEvents() {
+	now := time.Now()
+	for _, t := range c.Service.Template.Due(now.Add(repeatLead)) {
+		t := t
+		loc := c.Service.Profile.Location(t.HostID)
+		start := t.NextRun
+		next := nextRun(t.Days, t.OpensAt, loc, start)
+		if !start.After(now) {
+			// missed while the bot was offline or posting kept failing
+			c.Service.Template.SetNext(t.ID, next)
+			continue
+		}
+		host, err := c.Ses.User(t.HostID)
+		args := parseArgs(t.Args)
+		if err != nil || args == nil {
+			c.Service.Template.SetNext(t.ID, next)
+			continue
+		}
+		args["start"] = start.In(loc).Format("2006-01-02 15:04")
+		id, err := c.launchTemplate(&t, args, host)
+		if err != nil {
+			// only tell the host on the first try so retries don't spam them
+			if start.Sub(now) > repeatLead-time.Minute {
+				msg := c.createMsgEmbed(
+					"Error: Couldn't Post Repeating Event", errThumbURL, "Template: "+t.Name, errColor,
+					format(
+						createFields("Reason", err.Error(), false),
+						createFields("Note", "I'll keep trying until the queue opens at "+formatTime(start, loc)+".", false),
+					))
+				c.sendDM(t.HostID, msg)
+			}
+			continue
+		}
+		c.Service.Template.SetNext(t.ID, next)
+		msg := c.createMsgEmbed(
+			"Repeating Event Posted!", checkThumbURL, "Queue ID: "+id, successColor,
+			format(
+				createFields("Template", t.Name, true),
+				createFields("Queue Opens", formatTime(start, loc), true),
+			))
+		c.sendDM(t.HostID, msg)
+	}
+}
+
+// parseDays reads a weekly pattern into a bit mask of weekdays
+//
+// Accepted patterns are daily, weekdays, weekends or a comma separated list
+// of days (sun,wed or sunday,wednesday)
+func parseDays(days string) (int, error) {
+	switch strings.ToLower(days) {
+	case "daily":
+		return 1<<7 - 1, nil
+	case "weekdays":
+		days = "mon,tue,wed,thu,fri"
+	case "weekends":
+		days = "sat,sun"
+	}
+	var mask int
+	for _, d := range strings.Split(strings.ToLower(days), ",") {
+		d = strings.TrimSpace(d)
+		if len(d) < 3 {
+			return 0, errors.New("unknown day " + d)
+		}
+		wd, ok := weekdays[d[:3]]
+		if !ok || !strings.HasPrefix(strings.ToLower(wd.String()), d) {
+			return 0, errors.New("unknown day " + d)
+		}
+		mask |= 1 << uint(wd)
+	}
+	return mask, nil
+}
+
+// formatDays prints a bit mask of weekdays (e.g. Sun, Wed)
+func formatDays(mask int) string {
+	if mask == 1<<7-1 {
+		return "Daily"
+	}
+	var days []string
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if mask&(1<<uint(wd)) != 0 {
+			days = append(days, wd.String()[:3])
+		}
+	}
+	return strings.Join(days, ", ")
+}
+
+// nextRun returns the first time after the given time that falls on one of
+// the days of the mask at the time of day in the location
+//
+// It returns the zero time if the pattern can't be read
+func nextRun(days int, at string, loc *time.Location, after time.Time) time.Time {
+	clock, err := time.Parse("15:04", at)
+	if err != nil || days == 0 {
+		return time.Time{}
+	}
+	local := after.In(loc)
+	for i := 0; i <= 7; i++ {
+		d := local.AddDate(0, 0, i)
+		t := time.Date(d.Year(), d.Month(), d.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+		if days&(1<<uint(t.Weekday())) != 0 && t.After(after) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// formatArgs writes arguments back as key="value" pairs sorted by key
+func formatArgs(args map[string]string) string {
+	var keys []string
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=\"" + args[k] + "\""
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDays(t *testing.T) {
+	tests := map[string]struct {
+		days    string
+		want    int
+		wantErr bool
+	}{
+		"daily":      {days: "daily", want: 127},
+		"weekdays":   {days: "weekdays", want: 62},
+		"weekends":   {days: "Weekends", want: 65},
+		"short list": {days: "sun,wed", want: 1 | 8},
+		"full names": {days: "sunday, wednesday", want: 1 | 8},
+		"unknown":    {days: "someday", wantErr: true},
+		"too short":  {days: "su", wantErr: true},
+		"misspelled": {days: "sunxday", wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseDays(tc.days)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseDays() err = %v; wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("parseDays() got = %d; want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	loc := time.FixedZone("UTC-4", -4*60*60)
+	// Monday 20:00 in loc
+	after := time.Date(2026, 10, 19, 20, 0, 0, 0, loc)
+	tests := map[string]struct {
+		days int
+		at   string
+		want time.Time
+	}{
+		"later today": {
+			days: 1 << uint(time.Monday),
+			at:   "21:00",
+			want: time.Date(2026, 10, 19, 21, 0, 0, 0, loc),
+		},
+		"same time next week": {
+			days: 1 << uint(time.Monday),
+			at:   "20:00",
+			want: time.Date(2026, 10, 26, 20, 0, 0, 0, loc),
+		},
+		"next matching day": {
+			days: 1<<uint(time.Sunday) | 1<<uint(time.Wednesday),
+			at:   "09:30",
+			want: time.Date(2026, 10, 21, 9, 30, 0, 0, loc),
+		},
+		"no days": {
+			days: 0,
+			at:   "09:30",
+		},
+		"bad time": {
+			days: 127,
+			at:   "9pm",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := nextRun(tc.days, tc.at, loc, after); !got.Equal(tc.want) {
+				t.Errorf("nextRun() got = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNextRunDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time.LoadLocation() err = %v", err)
+	}
+	tests := map[string]struct {
+		after time.Time
+		days  int
+		at    string
+		want  time.Time
+	}{
+		"clocks fall back": {
+			// Saturday before daylight saving time ends
+			after: time.Date(2026, 10, 31, 21, 0, 0, 0, loc),
+			days:  1 << uint(time.Sunday),
+			at:    "21:00",
+			want:  time.Date(2026, 11, 1, 21, 0, 0, 0, loc),
+		},
+		"clocks spring forward": {
+			// Saturday before daylight saving time starts
+			after: time.Date(2026, 3, 7, 21, 0, 0, 0, loc),
+			days:  1 << uint(time.Sunday),
+			at:    "21:00",
+			want:  time.Date(2026, 3, 8, 21, 0, 0, 0, loc),
+		},
+		"daily across the change": {
+			after: time.Date(2026, 11, 1, 0, 30, 0, 0, loc),
+			days:  127,
+			at:    "09:30",
+			want:  time.Date(2026, 11, 1, 9, 30, 0, 0, loc),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := nextRun(tc.days, tc.at, loc, tc.after)
+			if !got.Equal(tc.want) {
+				t.Fatalf("nextRun() got = %v; want %v", got, tc.want)
+			}
+			if h, m, _ := got.In(loc).Clock(); h != tc.want.Hour() || m != tc.want.Minute() {
+				t.Errorf("nextRun() clock = %02d:%02d; want %s", h, m, tc.at)
+			}
+		})
+	}
+}
+
+func TestFormatArgs(t *testing.T) {
+	args := map[string]string{"msg": "ironwood bed", "limit": "5"}
+	got := formatArgs(args)
+	if want := `limit="5" msg="ironwood bed"`; got != want {
+		t.Errorf("formatArgs() got = %s; want %s", got, want)
+	}
+	if again := parseArgs(got); len(again) != 2 || again["msg"] != "ironwood bed" || again["limit"] != "5" {
+		t.Errorf("parseArgs(formatArgs()) got = %v; want %v", again, args)
+	}
+}
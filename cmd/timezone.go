@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"strings"
+)
+
+// Timezone lets users set the timezone used to read and display
+// their event times
+//
+// The command usage should look like: ?timezone America/New_York
+func Timezone(cmdInfo CommandInfo) {
+	user := cmdInfo.Msg.Author
+	if len(cmdInfo.CmdOps) == 1 {
+		// print current timezone
+		loc := cmdInfo.Service.Profile.Location(user.ID)
+		msg := cmdInfo.createMsgEmbed(
+			"Your Timezone", listThumbURL, loc.String(), listColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"timezone America/New_York", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	tz := strings.TrimSpace(cmdInfo.CmdOps[1])
+	if err := cmdInfo.Service.Profile.SetTimezone(user.ID, tz); err != nil {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Set Timezone", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"timezone America/New_York", true),
+				createFields("EXAMPLE", cmdInfo.Prefix+"timezone Europe/London", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	msg := cmdInfo.createMsgEmbed(
+		"Timezone Updated", checkThumbURL, tz, successColor,
+		format(
+			createFields("User", user.Mention(), true),
+		))
+	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+}
@@ -1,21 +1,44 @@
 package cmd
 
 import (
-	"bytes"
+	"errors"
 	"strconv"
 	"strings"
-	"unicode"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/yiping-allison/isabelle/models"
 )
 
 type trade struct {
-	// item user is willing to trade
+	// whether the user has the item for trade (ft) or is looking for it (lf)
+	direction string
+
+	// item user is willing to trade (or looking for)
 	item string
 
 	// msg (preferably) about what item they're looking for to trade
 	msg string
+
+	// optional lines of items with quantities the user has and wants; have
+	// replaces item and want can replace msg (the other way around for lf)
+	have string
+	want string
+
+	// optional reputation users need to offer
+	minRep string
+
+	// optional category used to route the listing
+	category string
+
+	// optional yes or no; sealed offers are sent by DM and only seen by
+	// the host and mods
+	sealed string
+
+	// optional auction settings; a starting bid makes the trade an auction
+	start     string
+	increment string
+	duration  string
 }
 
 // Trade will handle trade options within the server
@@ -27,11 +50,29 @@ func Trade(cmdInfo CommandInfo) {
 
 	if _, err := strconv.Atoi(cmdInfo.CmdOps[1]); err == nil {
 		// This is a list command - print all currently offered to tradeID
+		t := cmdInfo.Service.Trade.GetTrade(cmdInfo.CmdOps[1])
+		if t.Auction {
+			printBids(cmdInfo, t)
+			return
+		}
+		if t.Sealed {
+			printSealedList(cmdInfo, t)
+			return
+		}
 		offers := cmdInfo.Service.Trade.GetAllOffers(cmdInfo.CmdOps[1])
 		printTradeList(offers, cmdInfo, cmdInfo.CmdOps[1])
 		return
 	}
 
+	switch strings.ToLower(cmdInfo.CmdOps[1]) {
+	case "complete":
+		completeTrade(cmdInfo)
+		return
+	case "accept":
+		acceptTrade(cmdInfo)
+		return
+	}
+
 	// if user doesn't exist in rep database, create a new one
 	if !cmdInfo.Service.Rep.Exists(user.ID) {
 		cmdInfo.newRep(user.ID)
@@ -54,8 +95,12 @@ func Trade(cmdInfo CommandInfo) {
 		return
 	}
 
-	// attempt to parse command
-	t := parseTradeCmd(strings.Join(cmdInfo.CmdOps[1:], " "))
+	// attempt to parse command; a leading ft or lf sets the direction
+	direction, args := models.TradeFT, cmdInfo.CmdOps[1:]
+	if d := strings.ToLower(args[0]); d == models.TradeFT || d == models.TradeLF {
+		direction, args = d, args[1:]
+	}
+	t := parseTradeCmd(direction, strings.Join(args, " "))
 	if t == nil {
 		// error parse failed
 		msg := cmdInfo.createMsgEmbed(
@@ -63,11 +108,33 @@ func Trade(cmdInfo CommandInfo) {
 			format(
 				createFields("Suggestion", "Try checking if you input the command correctly.", false),
 				createFields("EXAMPLE", cmdInfo.Prefix+"trade item=\"blue mountain coffee\" msg=\"looking for geisha coffee\"", false),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade have=\"3x gold nugget + 10 star fragments\" want=\"2 nmt\"", false),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade lf item=\"royal crown\" msg=\"paying bells\"", false),
 			))
 		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
 		return
 	}
 
+	have, want, err := t.lines()
+	if err != nil {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Create Trade", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade have=\"3x gold nugget + 10 star fragments\" want=\"2 nmt\"", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	var item string
+	var suggestions []string
+	if have, item, suggestions = cmdInfo.resolveLines(have); item == "" {
+		want, item, suggestions = cmdInfo.resolveLines(want)
+	}
+	if item != "" {
+		cmdInfo.suggestItems("Error: Couldn't Create Trade", item, suggestions)
+		return
+	}
+
 	// generate trade id
 	id := generateID(1000, 9999)
 
@@ -82,34 +149,403 @@ func Trade(cmdInfo CommandInfo) {
 		return
 	}
 
+	minRep, err := parseMinRep(t.minRep)
+	if err != nil {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Create Trade", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade item=\"gold nugget\" minrep=\"3\" msg=\"looking for nmt\"", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+
+	category, ok := parseCategory(t.category)
+	if !ok {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Create Trade", errThumbURL, "Unknown category: "+t.category, errColor,
+			format(
+				createFields("Categories", strings.Join(models.TradeCategories, ", "), false),
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade item=\"ironwood kitchenette\" category=\"diy\" msg=\"looking for bells\"", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+
+	a, err := parseAuction(t.start, t.increment, t.duration)
+	if err == nil && a != nil && t.direction == models.TradeLF {
+		err = errors.New("looking for listings can't be auctions")
+	}
+	if err != nil {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Create Trade", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade item=\"crown\" msg=\"auction!\" start=\"100k\" increment=\"10k\" duration=\"2h\"", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+
+	sealed, err := parseSealed(t.sealed)
+	if err != nil || (sealed && a != nil) {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Create Trade", errThumbURL, "Sealed must be yes or no and can't be used with auctions.", errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade item=\"royal crown\" msg=\"best offer\" sealed=\"yes\"", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+
 	// Add trade event
-	cmdInfo.Service.Trade.AddTrade(id, user)
+	data := &models.TradeData{
+		DiscordUser: user,
+		Item:        lineSummary(have),
+		Msg:         t.msg,
+		Direction:   t.direction,
+		Have:        have,
+		Want:        want,
+		MinRep:      minRep,
+		Category:    category,
+		Sealed:      sealed,
+	}
+	if t.direction == models.TradeLF {
+		data.Item = lineSummary(want)
+	}
+	if a != nil {
+		data.Auction = true
+		data.StartBid = a.start
+		data.Increment = a.increment
+		data.Ends = time.Now().Add(a.duration)
+	}
+	cmdInfo.Service.Trade.AddTrade(id, data)
 	// Add trade tracking to user
 	expire := cmdInfo.Service.Trade.GetExpiration(id)
 	cmdInfo.Service.User.AddTrade(user, id, expire)
 
-	// retrieve reps from database
-	reps := cmdInfo.Service.Rep.GetRep(user.ID)
-
 	// Print Trade Offer
-	msg := cmdInfo.createMsgEmbed(
-		"Trade", tradeThumbURL, "Trade ID: "+id, tradeColor,
+	msg := cmdInfo.tradeEmbed(id)
+	r := cmdInfo.route(cmdInfo.TradeRoutes, category)
+	m, err := cmdInfo.Ses.ChannelMessageSendComplex(r.ChannelID, &discordgo.MessageSend{
+		Content: r.rolePing(),
+		Embed:   msg,
+	})
+	if err == nil {
+		cmdInfo.Service.Trade.SetListing(id, m.ChannelID, m.ID)
+	}
+	if t.direction == models.TradeFT {
+		notified := cmdInfo.notifySubscribers(cmdInfo.Service.Subscription.Trades(data.Item), user.ID, msg)
+		cmdInfo.notifyWishlists(lineItems(have), user.ID, id, notified)
+	}
+	cmdInfo.matchListings(id)
+	cmdInfo.Ses.ChannelMessageSend(cmdInfo.BotChID, "Listing Posted!")
+}
+
+// completeTrade confirms that the trade host traded with someone who made an
+// offer which lets both of them file rep applications about each other
+//
+// The command usage should look like: ?trade complete 1234 @user
+func completeTrade(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) != 4 {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Wrong Arguments", errThumbURL, "Try checking your syntax.", errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade complete 1234 @user", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	tradeID := cmdInfo.CmdOps[2]
+	userID := stripPing(cmdInfo.CmdOps[3])
+	t := cmdInfo.Service.Trade.GetTrade(tradeID)
+	if t.DiscordUser == nil || t.DiscordUser.ID != cmdInfo.Msg.Author.ID {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Complete Trade", errThumbURL, "Trade ID: "+tradeID, errColor,
+			format(
+				createFields("Suggestion", "Only the host of an active trade can complete it.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	var offered bool
+	for _, o := range t.Offers {
+		if o.User.ID == userID {
+			offered = true
+		}
+	}
+	if !offered {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Complete Trade", errThumbURL, "This user hasn't made an offer.", errColor,
+			format(
+				createFields("User", mentionUser(userID), true),
+				createFields("Trade ID", tradeID, true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	cmdInfo.confirmInteraction(&models.Interaction{
+		Kind:      models.InteractionTrade,
+		ListingID: tradeID,
+		Name:      t.Item,
+		HostID:    t.DiscordUser.ID,
+		UserID:    userID,
+	})
+}
+
+// acceptTrade picks the winning offer of a trade, lets every offerer know
+// and closes the listing
+//
+// The command usage should look like: ?trade accept 1234 @user
+func acceptTrade(cmdInfo CommandInfo) {
+	if len(cmdInfo.CmdOps) != 4 {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Wrong Arguments", errThumbURL, "Try checking your syntax.", errColor,
+			format(
+				createFields("EXAMPLE", cmdInfo.Prefix+"trade accept 1234 @user", true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	host := cmdInfo.Msg.Author
+	tradeID := cmdInfo.CmdOps[2]
+	userID := stripPing(cmdInfo.CmdOps[3])
+	t := cmdInfo.Service.Trade.GetTrade(tradeID)
+	if t.DiscordUser == nil || t.DiscordUser.ID != host.ID {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Accept Offer", errThumbURL, "Trade ID: "+tradeID, errColor,
+			format(
+				createFields("Suggestion", "Only the host of an active trade can accept an offer.", false),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	o, err := cmdInfo.Service.Trade.UpdateOffer(tradeID, userID, models.OfferAccepted, "")
+	if err != nil {
+		msg := cmdInfo.createMsgEmbed(
+			"Error: Couldn't Accept Offer", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("User", mentionUser(userID), true),
+				createFields("Trade ID", tradeID, true),
+			))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+
+	if err := cmdInfo.Service.Trade.Close(tradeID, host, nil, cmdInfo.AdminRole); err != nil {
+		return
+	}
+	cmdInfo.finishTrade(t, userID, o.Offer)
+}
+
+// finishTrade wraps up a closed trade or ended auction with its winner; it
+// records the trade, lets everyone who offered or bid know and announces
+// the result
+func (c CommandInfo) finishTrade(t models.TradeData, userID, offer string) {
+	host := t.DiscordUser
+	c.Service.User.RemoveTrade(t.ID, host)
+	for _, o := range t.Offers {
+		c.Service.User.RemoveOffer(t.ID, o.User)
+	}
+
+	// the accepted trade unlocks rep applications between both sides
+	c.Service.Interaction.Record(&models.Interaction{
+		Kind:      models.InteractionTrade,
+		ListingID: t.ID,
+		Name:      t.Item,
+		HostID:    host.ID,
+		UserID:    userID,
+	})
+	c.Service.Ledger.Record(&models.TradeRecord{
+		TradeID:  t.ID,
+		Item:     t.Item,
+		Category: t.Category,
+		Offer:    offer,
+		Sealed:   t.Sealed,
+		HostID:   host.ID,
+		UserID:   userID,
+	})
+
+	action, won, label, note := "Trade Accepted", "Your Offer Was Accepted!", "Accepted Offer",
+		"The host accepted another offer. Thank you for offering!"
+	if t.Auction {
+		action, won, label, note = "Auction Won", "You Won the Auction!", "Winning Bid",
+			"Someone else won the auction. Thank you for bidding!"
+	}
+	c.logAction(action,
+		createFields("Trade ID", t.ID, true),
+		createFields("Host", host.Mention(), true),
+		createFields("Winner", mentionUser(userID), true),
+		createFields(label, offer, false),
+	)
+
+	others := make(map[string]bool)
+	for _, o := range t.Offers {
+		if o.Open() {
+			others[o.User.ID] = true
+		}
+	}
+	for _, b := range t.Bids {
+		others[b.User.ID] = true
+	}
+	delete(others, userID)
+	for id := range others {
+		dm := c.createMsgEmbed(
+			"Trade Completed", tradeThumbURL, "Trade ID: "+t.ID, tradeColor,
+			format(
+				createFields("Item", strings.Title(t.Item), true),
+				createFields("Note", note, false),
+			))
+		c.sendDM(id, dm)
+	}
+	dm := c.createMsgEmbed(
+		won, checkThumbURL, "Trade ID: "+t.ID, successColor,
 		format(
-			createFields("Trader", user.Mention(), true),
-			createFields("Reputation", strconv.Itoa(reps), true),
-			createFields("Trade Listing", strings.Title(t.item), false),
-			createFields("Message", strings.Title(t.msg), false),
-		),
+			createFields("Item", strings.Title(t.Item), true),
+			createFields("Host", host.Mention(), true),
+			createFields(label, offer, false),
+			createFields("Next Steps", "Message the host to set up the trade. Afterwards you can leave a rep application with "+
+				c.Prefix+"rep "+host.Mention()+" "+t.ID+" your message", false),
+		))
+	c.sendDM(userID, dm)
+
+	winner := createFields(label, mentionUser(userID)+": "+offer, false)
+	if t.Sealed {
+		// sealed offers stay between the host, the offerer and the mods
+		winner = createFields("Winner", mentionUser(userID), false)
+	}
+	embed := c.createMsgEmbed(
+		"Trade "+t.ID+" Completed!", checkThumbURL, "Thank you for trading!", successColor,
+		format(
+			createFields("Host", host.Mention(), true),
+			winner,
+			createFields("Rep", "Both of you can now file a rep application with "+c.Prefix+"rep @user "+t.ID+" message", false),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.PublicChID, embed)
+	r := c.route(c.TradeRoutes, t.Category)
+	c.Ses.ChannelMessageSendEmbed(r.ChannelID, embed)
+}
+
+// tradeEmbed builds the listing embed of a trade from its current state
+func (c CommandInfo) tradeEmbed(tradeID string) *discordgo.MessageEmbed {
+	t := c.Service.Trade.GetTrade(tradeID)
+	rep := c.Service.Rep.GetRep(t.DiscordUser.ID)
+	fields := format(
+		createFields("Trader", t.DiscordUser.Mention(), true),
+		createFields("Reputation", strconv.Itoa(rep), true),
 	)
-	cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.ListingID, msg)
-	cmdInfo.Ses.ChannelMessageSend(cmdInfo.BotChID, "Listing Posted!")
+	if t.MinRep > 0 {
+		fields = append(fields, createFields("Minimum Rep", strconv.Itoa(t.MinRep), true))
+	}
+	if t.Category != "" {
+		fields = append(fields, createFields("Category", strings.Title(t.Category), true))
+	}
+	if len(t.Have) > 0 {
+		fields = append(fields, createFields("Have", formatLines(t.Have, "\n"), false))
+	}
+	if len(t.Want) > 0 {
+		fields = append(fields, createFields("Want", formatLines(t.Want, "\n"), false))
+	}
+	if t.Msg != "" {
+		fields = append(fields, createFields("Message", strings.Title(t.Msg), false))
+	}
+	if t.Sealed {
+		fields = append(fields, createFields("Sealed Offers", "Only the host sees offers. DM me "+
+			c.Prefix+"offer "+tradeID+" your offer", false))
+	}
+	title := "Trade"
+	if t.Direction == models.TradeLF {
+		title = "Looking For"
+	}
+	if t.Auction {
+		title = "Auction"
+		loc := c.Service.Profile.Location(t.DiscordUser.ID)
+		high := "No bids yet"
+		if b, ok := t.HighBid(); ok {
+			high = formatBells(b.Amount) + " by " + b.User.Mention()
+		}
+		fields = append(fields,
+			createFields("Starting Bid", formatBells(t.StartBid), true),
+			createFields("Increment", formatBells(t.Increment), true),
+			createFields("Ends", formatTime(t.Ends, loc), true),
+			createFields("High Bid", high, false),
+			createFields("Bid", c.Prefix+"bid "+tradeID+" "+strconv.Itoa(t.MinBid()), false),
+		)
+	}
+	return c.createMsgEmbed(title, tradeThumbURL, "Trade ID: "+tradeID, tradeColor, fields)
+}
+
+// updateTrade edits a trade's listing message to match its current state
+func (c CommandInfo) updateTrade(tradeID string) {
+	t := c.Service.Trade.GetTrade(tradeID)
+	if t.MessageID == "" {
+		return
+	}
+	c.Ses.ChannelMessageEditEmbed(t.ChannelID, t.MessageID, c.tradeEmbed(tradeID))
+}
+
+// printSealedList sends the offers of a sealed trade to the host or a mod
+// by DM; everyone else only sees how many offers there are
+func printSealedList(cmdInfo CommandInfo, t models.TradeData) {
+	user := cmdInfo.Msg.Author
+	if user.ID != t.DiscordUser.ID && !isAdmin(cmdInfo.Msg.Member.Roles, cmdInfo.AdminRole) {
+		msg := cmdInfo.createMsgEmbed("Sealed Offers", tradeThumbURL, "TradeID: "+t.ID, tradeColor, format(
+			createFields("Offers", strconv.Itoa(openOffers(t))+" offers received. Only the host can see them.", false),
+		))
+		cmdInfo.Ses.ChannelMessageSendEmbed(cmdInfo.BotChID, msg)
+		return
+	}
+	ch, err := cmdInfo.Ses.UserChannelCreate(user.ID)
+	if err != nil {
+		return
+	}
+	dm := cmdInfo
+	dm.BotChID = ch.ID
+	printTradeList(t.Offers, dm, t.ID)
+	cmdInfo.Ses.ChannelMessageSend(cmdInfo.BotChID, user.Mention()+": I sent you the sealed offers by DM.")
+}
+
+// openOffers returns the amount of offers of a trade that are still open
+func openOffers(t models.TradeData) int {
+	n := 0
+	for _, o := range t.Offers {
+		if o.Open() {
+			n++
+		}
+	}
+	return n
+}
+
+// parseCategory parses the category of a trade; empty means no category
+//
+// Singular names such as ticket are accepted too
+func parseCategory(str string) (string, bool) {
+	if str == "" {
+		return "", true
+	}
+	for _, c := range models.TradeCategories {
+		if str == c || str+"s" == c {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// parseSealed parses the sealed key of a trade; empty means not sealed
+func parseSealed(str string) (bool, error) {
+	switch str {
+	case "", "no", "off":
+		return false, nil
+	case "yes", "on":
+		return true, nil
+	}
+	return false, errors.New("sealed must be yes or no")
 }
 
 // printTradeList handles printing large amounts of trade offers (since trade offers has no limit)
 func printTradeList(offers []models.TradeOfferer, cmdInfo CommandInfo, tradeID string) {
 	var fields []*discordgo.MessageEmbedField
 	for _, o := range offers {
-		fields = append(fields, createFields(o.User.String(), o.Offer, true))
+		fields = append(fields, createFields(o.User.String(), offerStatus(o), true))
 	}
 	for i := 0; i < len(fields); i += 15 {
 		j := i + 15
@@ -121,45 +557,80 @@ func printTradeList(offers []models.TradeOfferer, cmdInfo CommandInfo, tradeID s
 	}
 }
 
+// tradeKeys are all arguments a trade command accepts
+var tradeKeys = []string{"item", "msg", "have", "want", "minrep", "category", "sealed", "start", "increment", "duration"}
+
 // parseTradeCmd will take a full command string and return a trade object
 // if the command was correctly parsed
 //
 // else, nil
-func parseTradeCmd(fullCmd string) *trade {
-	f := func(r rune) bool {
-		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && !unicode.IsSpace(r)
-	}
-	bytes := bytes.FieldsFunc([]byte(fullCmd), f)
-	var parsedCmd []string
-	for _, e := range bytes {
-		parsedCmd = append(parsedCmd, strings.ToLower(strings.TrimSpace(string(e))))
-	}
-	if len(parsedCmd) != 4 || !validTrade(parsedCmd) {
+func parseTradeCmd(direction, fullCmd string) *trade {
+	args := parseArgs(fullCmd)
+	if !validTrade(direction, args) {
 		// error - syntax not parsed correctly
 		return nil
 	}
-	var t trade
-	for i := 0; i < len(parsedCmd); i += 2 {
-		if parsedCmd[i] == "item" {
-			t.item = parsedCmd[i+1]
-			continue
+	return &trade{
+		direction: direction,
+		item:      strings.ToLower(args["item"]),
+		msg:       strings.ToLower(args["msg"]),
+		have:      args["have"],
+		want:      args["want"],
+		sealed:    strings.ToLower(args["sealed"]),
+		minRep:    args["minrep"],
+		category:  strings.ToLower(args["category"]),
+		start:     args["start"],
+		increment: args["increment"],
+		duration:  args["duration"],
+	}
+}
+
+// lines parses the have and want lines of a trade; a single item counts as
+// one have line (or want line for lf listings)
+func (t *trade) lines() ([]models.TradeLine, []models.TradeLine, error) {
+	main, other := t.have, t.want
+	if t.direction == models.TradeLF {
+		main, other = t.want, t.have
+	}
+	mainLines := []models.TradeLine{{Item: t.item, Quantity: 1}}
+	if main != "" {
+		var err error
+		if mainLines, err = parseLines(main); err != nil {
+			return nil, nil, err
 		}
-		if parsedCmd[i] == "msg" {
-			t.msg = parsedCmd[i+1]
-			continue
+	}
+	var otherLines []models.TradeLine
+	if other != "" {
+		var err error
+		if otherLines, err = parseLines(other); err != nil {
+			return nil, nil, err
 		}
 	}
-	return &t
+	if t.direction == models.TradeLF {
+		return otherLines, mainLines, nil
+	}
+	return mainLines, otherLines, nil
 }
 
-// validTrade checks if the given command contains the two keywords:
-// item & msg
-func validTrade(cmds []string) bool {
-	var tmp []string
-	for _, i := range cmds {
-		if i == "item" || i == "msg" {
-			tmp = append(tmp, i)
+// validTrade checks if the given command contains either item or have,
+// either msg or want and only other keys listed in tradeKeys
+//
+// Looking for (lf) listings need either item or want and either msg or have
+func validTrade(direction string, args map[string]string) bool {
+	main, other := "have", "want"
+	if direction == models.TradeLF {
+		main, other = "want", "have"
+	}
+	if args == nil || (args["item"] == "") == (args[main] == "") {
+		return false
+	}
+	if args["msg"] == "" && args[other] == "" {
+		return false
+	}
+	for k := range args {
+		if !contains(tradeKeys, k) {
+			return false
 		}
 	}
-	return len(tmp) == 2
+	return true
 }
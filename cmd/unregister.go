@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/yiping-allison/isabelle/models"
 )
 
 // Unregister allows a queue user to remove themselves from the queue
@@ -42,18 +43,22 @@ func (c CommandInfo) removeFromEvent(eventID string, user *discordgo.User) {
 	}
 
 	// remove user
-	c.Service.Event.Remove(eventID, user)
+	promoted := c.Service.Event.Remove(eventID, user)
 	// Remove tracking on user
 	c.Service.User.RemoveQueue(eventID, user)
 
 	// successfully removed user
 	msg := c.createMsgEmbed(
-		"Removed from Event", checkThumbURL, "Queue ID: "+c.CmdOps[2],
+		"Removed from Event", checkThumbURL, "Queue ID: "+eventID,
 		successColor, format(
 			createFields("User", user.Mention(), true),
 			createFields("Suggestion", "Feel free to queue for any other events or create your own.", false),
 		))
 	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+	if promoted != nil {
+		c.notifyPromoted(eventID, []*discordgo.User{promoted})
+	}
+	c.refreshPositions(eventID)
 }
 
 // helper func to remove user's offer from trade event
@@ -69,19 +74,30 @@ func (c CommandInfo) removeFromTrade(tradeID string, user *discordgo.User) {
 		return
 	}
 
-	offer := c.Service.Trade.GetOffer(tradeID, user.ID)
-	// remove user
-	c.Service.Trade.Remove(tradeID, user)
+	// withdraw offer
+	o, err := c.Service.Trade.UpdateOffer(tradeID, user.ID, models.OfferWithdrawn, "")
+	if err != nil {
+		msg := c.createMsgEmbed(
+			"Error: Couldn't Withdraw Offer", errThumbURL, strings.Title(err.Error()), errColor,
+			format(
+				createFields("Trade ID", tradeID, true),
+			))
+		c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+		return
+	}
 	// remove tracking on user
 	c.Service.User.RemoveOffer(tradeID, user)
 
-	// successfully removed user
-	msg := c.createMsgEmbed(
-		"User Withdrew From Trade", checkThumbURL, "Trade ID: "+tradeID,
-		successColor, format(
-			createFields("User", user.Mention(), true),
-			createFields("Offer", offer, true),
-			createFields("Suggestion", "Feel free to offer for any other trades or create your own.", false),
-		))
-	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+	// successfully withdrew offer; let the host know without revealing
+	// sealed offers
+	fields := format(createFields("User", user.Mention(), true))
+	if !c.Service.Trade.GetTrade(tradeID).Sealed {
+		fields = append(fields, createFields("Offer", o.Offer, true))
+	}
+	fields = append(fields, createFields("Suggestion", "Feel free to offer for any other trades or create your own.", false))
+	msg := c.createMsgEmbed("User Withdrew From Trade", checkThumbURL, "Trade ID: "+tradeID, successColor, fields)
+	c.Ses.ChannelMessageSendComplex(c.PublicChID, &discordgo.MessageSend{
+		Content: c.Service.Trade.GetHost(tradeID).Mention() + ": An offer to your trade was withdrawn.",
+		Embed:   msg,
+	})
 }
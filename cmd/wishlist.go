@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Wishlist lets users keep a list of items they're looking for; they are
+// messaged whenever a matching trade is listed
+//
+// The command usage should look like:
+//
+// ?wishlist add "geisha coffee"
+//
+// ?wishlist remove "geisha coffee"
+//
+// Using only ?wishlist prints the wishlist and ?wishlist remove clears it
+func Wishlist(cmdInfo CommandInfo) {
+	user := cmdInfo.Msg.Author
+	if len(cmdInfo.CmdOps) == 1 {
+		cmdInfo.printWishlist()
+		return
+	}
+	item := subKeyword(cmdInfo.CmdOps[2:])
+	var err error
+	switch strings.ToLower(cmdInfo.CmdOps[1]) {
+	case "add":
+		err = cmdInfo.Service.Wishlist.Add(user.ID, item)
+	case "remove":
+		if item == "" {
+			err = cmdInfo.Service.Wishlist.Clear(user.ID)
+		} else {
+			err = cmdInfo.Service.Wishlist.Remove(user.ID, item)
+		}
+	default:
+		cmdInfo.wishlistSyntaxError("Try checking your syntax.")
+		return
+	}
+	if err != nil {
+		cmdInfo.wishlistSyntaxError(strings.Title(err.Error()))
+		return
+	}
+	cmdInfo.printWishlist()
+}
+
+// printWishlist prints every item on the user's wishlist
+func (c CommandInfo) printWishlist() {
+	user := c.Msg.Author
+	var items []string
+	for _, w := range c.Service.Wishlist.ByUser(user.ID) {
+		items = append(items, strings.Title(w.Item))
+	}
+	fields := format(createFields("Items", "Your wishlist is empty.", false))
+	if len(items) > 0 {
+		fields = format(createFields("Items", strings.Join(items, "\n"), false))
+	}
+	msg := c.createMsgEmbed("Your Wishlist", listThumbURL, user.Mention(), listColor, fields)
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// wishlistSyntaxError prints why a wishlist change failed with examples
+func (c CommandInfo) wishlistSyntaxError(reason string) {
+	msg := c.createMsgEmbed(
+		"Error: Couldn't Update Wishlist", errThumbURL, reason, errColor,
+		format(
+			createFields("EXAMPLE", c.Prefix+"wishlist add \"geisha coffee\"", true),
+			createFields("EXAMPLE", c.Prefix+"wishlist remove \"geisha coffee\"", true),
+			createFields("EXAMPLE", c.Prefix+"wishlist", true),
+		))
+	c.Ses.ChannelMessageSendEmbed(c.BotChID, msg)
+}
+
+// notifyWishlists messages every user with a wishlist item matching an item
+// of a new trade, skipping users in skip (e.g. subscribers who were already
+// told)
+func (c CommandInfo) notifyWishlists(items []string, hostID, tradeID string, skip map[string]bool) {
+	embeds := make(map[string]*discordgo.MessageEmbed)
+	for _, w := range c.Service.Wishlist.All() {
+		if skip[w.UserID] || embeds[w.UserID] != nil {
+			continue
+		}
+		item, ok := matchAny(w.Item, items)
+		if !ok {
+			continue
+		}
+		embeds[w.UserID] = c.createMsgEmbed(
+			"Wishlist Match: "+strings.Title(item), tradeThumbURL, "Trade ID: "+tradeID, tradeColor,
+			format(
+				createFields("Wishlist Item", strings.Title(w.Item), true),
+				createFields("Host", mentionUser(hostID), true),
+				createFields("Make an Offer", c.Prefix+"offer "+tradeID+" your offer", false),
+			))
+	}
+	for userID, embed := range embeds {
+		c.notifyUser(userID, hostID, embed)
+	}
+}
+
+// matchAny returns the first listed item matching the wished item
+func matchAny(wish string, items []string) (string, bool) {
+	for _, item := range items {
+		if matchItem(wish, item) {
+			return item, true
+		}
+	}
+	return "", false
+}
+
+// matchItem returns true if every word of the wished item shows up in the
+// listed item
+//
+// Both are normalized first so case and punctuation don't matter and words
+// match if they only differ by a plural or a single typo; e.g. "geisha coffee"
+// matches "Geisha Coffee Beans"
+func matchItem(wish, item string) bool {
+	wishWords := itemWords(wish)
+	listed := itemWords(item)
+	if len(wishWords) == 0 {
+		return false
+	}
+	for _, w := range wishWords {
+		found := false
+		for _, i := range listed {
+			if sameWord(w, i) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// itemWords lower cases an item name and splits it into words; apostrophes
+// are dropped and any other punctuation separates words
+func itemWords(item string) []string {
+	item = strings.NewReplacer("'", "", "’", "").Replace(strings.ToLower(item))
+	return strings.FieldsFunc(item, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// sameWord returns true if two normalized words are equal, differ only by a
+// plural ending or (for longer words) by one typo
+func sameWord(a, b string) bool {
+	if a == b || plural(a, b) || plural(b, a) {
+		return true
+	}
+	if len(a) < 5 || len(b) < 5 {
+		return false
+	}
+	return editDistance(a, b) <= 1
+}
+
+// plural returns true if p is the plural of w (e.g. bean and beans)
+func plural(w, p string) bool {
+	return p == w+"s" || p == w+"es"
+}
+
+// editDistance returns the Levenshtein distance between two words
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import "testing"
+
+func TestMatchItem(t *testing.T) {
+	tests := map[string]struct {
+		wish, item string
+		want       bool
+	}{
+		"exact": {
+			wish: "gold nugget",
+			item: "gold nugget",
+			want: true,
+		},
+		"extra words in listing": {
+			wish: "geisha coffee",
+			item: "Geisha Coffee Beans",
+			want: true,
+		},
+		"word order": {
+			wish: "coffee geisha",
+			item: "geisha coffee",
+			want: true,
+		},
+		"plural": {
+			wish: "gold nuggets",
+			item: "gold nugget",
+			want: true,
+		},
+		"es plural": {
+			wish: "wooden box",
+			item: "wooden boxes",
+			want: true,
+		},
+		"punctuation and case": {
+			wish: "ironwood-dresser",
+			item: "Ironwood Dresser!",
+			want: true,
+		},
+		"possessive": {
+			wish: "bunny day wand",
+			item: "Bunny's Day Wand",
+			want: true,
+		},
+		"typo in long word": {
+			wish: "kitchenete",
+			item: "ironwood kitchenette",
+			want: true,
+		},
+		"typo in short word not matched": {
+			wish: "gold",
+			item: "bold",
+			want: false,
+		},
+		"missing word": {
+			wish: "geisha coffee",
+			item: "coffee beans",
+			want: false,
+		},
+		"empty wish": {
+			wish: "",
+			item: "coffee",
+			want: false,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := matchItem(tc.wish, tc.item); got != tc.want {
+				t.Fatalf("matchItem(%q, %q) = %v; want %v", tc.wish, tc.item, got, tc.want)
+			}
+		})
+	}
+}
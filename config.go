@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+
+	"github.com/yiping-allison/isabelle/cmd"
 )
 
 // BotConfig represents bot configurations
@@ -28,6 +30,20 @@ type BotConfig struct {
 
 	// ID of Channel to post application listings
 	AppID string `json:"appID"`
+
+	// ID of channel to post host and moderation logs
+	LogID string `json:"logID"`
+
+	// Optional listing channels and role pings per event type and
+	// trade category; unrouted listings go to ListingID
+	Routes RoutesConfig `json:"routes"`
+}
+
+// RoutesConfig maps event types (e.g. turnip) and trade categories
+// (e.g. diy) to where their listings are posted
+type RoutesConfig struct {
+	Events map[string]cmd.Route `json:"events"`
+	Trades map[string]cmd.Route `json:"trades"`
 }
 
 // PostgresConfig represents metadata required to start and maintain postgres
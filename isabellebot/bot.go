@@ -36,13 +36,20 @@ type Bot struct {
 
 	// Channel ID of rep applications
 	App string
+
+	// Channel ID of host and moderation logs
+	Log string
+
+	// Listing channels and role pings per event type and trade category
+	EventRoutes map[string]cmd.Route
+	TradeRoutes map[string]cmd.Route
 }
 
 // New creates a new daisymae bot instance and loads bot commands.
 //
 // It will return the finished bot and nil upon success or
 // empty bot and err upon failure
-func New(botKey, admin, listing, botCh, app string) (*Bot, error) {
+func New(botKey, admin, listing, botCh, app, log string) (*Bot, error) {
 	if botKey == "" {
 		return nil, errors.New("isabellebot: you need to input a botKey in the .config file")
 	}
@@ -61,11 +68,14 @@ func New(botKey, admin, listing, botCh, app string) (*Bot, error) {
 		Listing:   listing,
 		BotCh:     botCh,
 		App:       app,
+		Log:       log,
 	}
 	isa.compileCommands()
 	// Add Handlers
 	isa.DS.AddHandler(isa.ready)
 	isa.DS.AddHandler(isa.handleMessage)
+	isa.DS.AddHandler(isa.reactionAdd)
+	isa.DS.AddHandler(isa.reactionRemove)
 	return isa, nil
 }
 
@@ -87,6 +97,65 @@ func (b *Bot) handleMessage(s *discordgo.Session, m *discordgo.MessageCreate) {
 	}
 }
 
+// reactionAdd joins the queue of an event when a user reacts to its listing
+func (b *Bot) reactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	b.react(s, r.MessageReaction, true)
+}
+
+// reactionRemove unregisters a user from an event when they remove their
+// reaction from its listing
+func (b *Bot) reactionRemove(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
+	b.react(s, r.MessageReaction, false)
+}
+
+// react runs the queue command on behalf of a user who reacted to a listing
+//
+// Errors are sent to the user's DMs since there's no command message to reply to
+func (b *Bot) react(s *discordgo.Session, r *discordgo.MessageReaction, join bool) {
+	// Ignore reactions added by the bot itself
+	if r.UserID == s.State.User.ID || r.Emoji.Name != cmd.JoinEmoji {
+		return
+	}
+	eventID, ok := b.Service.Event.ByMessage(r.MessageID)
+	if !ok {
+		return
+	}
+	user, err := s.User(r.UserID)
+	if err != nil || user.Bot {
+		return
+	}
+	ci := cmd.CommandInfo{
+		AdminRole: b.AdminRole,
+		Ses:       s,
+		Msg: &discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: r.ChannelID,
+				GuildID:   r.GuildID,
+				Author:    user,
+			},
+		},
+		Service:     b.Service,
+		ListingID:   b.Listing,
+		EventRoutes: b.EventRoutes,
+		TradeRoutes: b.TradeRoutes,
+		BotChID:     b.BotCh,
+		PublicChID:  b.BotCh,
+		AppID:       b.App,
+		LogID:       b.Log,
+		Reacted:     true,
+		Prefix:      b.Prefix,
+	}
+	cmd.React(ci, eventID, join)
+}
+
+// dmCommands are the commands which may also be sent to the bot by DM so
+// sealed offers and the host's replies to them stay private
+var dmCommands = map[string]bool{
+	"offer":   true,
+	"counter": true,
+	"decline": true,
+}
+
 // Command represents a discord bot command
 type Command struct {
 	Cmd func(cmd.CommandInfo)
@@ -102,12 +171,17 @@ type Command struct {
 //
 // ?search help
 func (b *Bot) processCmd(s *discordgo.Session, m *discordgo.MessageCreate) {
-	if m.ChannelID != b.BotCh {
-		// Command must be posted in bot channel; if not, command won't be processed
-		return
-	}
 	cmds := regexp.MustCompile("\\s+").Split(m.Content[len(b.Prefix):], -1)
 	trim := strings.TrimPrefix(cmds[0], b.Prefix)
+	replyCh := b.BotCh
+	if m.ChannelID != b.BotCh {
+		// Command must be posted in bot channel or be a DM command sent by
+		// DM; if not, command won't be processed
+		if m.GuildID != "" || !dmCommands[trim] {
+			return
+		}
+		replyCh = m.ChannelID
+	}
 	res := b.find(trim)
 	if res == nil {
 		// Command not found
@@ -118,22 +192,46 @@ func (b *Bot) processCmd(s *discordgo.Session, m *discordgo.MessageCreate) {
 		commands = append(commands, key)
 	}
 	ci := cmd.CommandInfo{
-		AdminRole: b.AdminRole,
-		Ses:       s,
-		Msg:       m,
-		Service:   b.Service,
-		ListingID: b.Listing,
-		BotChID:   b.BotCh,
-		AppID:     b.App,
-		Prefix:    b.Prefix,
-		CmdName:   trim,
-		CmdOps:    cmds,
-		CmdList:   commands,
+		AdminRole:   b.AdminRole,
+		Ses:         s,
+		Msg:         m,
+		Service:     b.Service,
+		ListingID:   b.Listing,
+		EventRoutes: b.EventRoutes,
+		TradeRoutes: b.TradeRoutes,
+		BotChID:     replyCh,
+		PublicChID:  b.BotCh,
+		AppID:       b.App,
+		LogID:       b.Log,
+		Prefix:      b.Prefix,
+		CmdName:     trim,
+		CmdOps:      cmds,
+		CmdList:     commands,
 	}
 	// Run command
 	res.Cmd(ci)
 }
 
+// Schedule runs the bot's timed tasks such as opening scheduled events
+//
+// This should only be called in the goroutine in main (ticker to run schedules)
+func (b *Bot) Schedule() {
+	ci := cmd.CommandInfo{
+		AdminRole:   b.AdminRole,
+		Ses:         b.DS,
+		Service:     b.Service,
+		ListingID:   b.Listing,
+		EventRoutes: b.EventRoutes,
+		TradeRoutes: b.TradeRoutes,
+		BotChID:     b.BotCh,
+		PublicChID:  b.BotCh,
+		AppID:       b.App,
+		LogID:       b.Log,
+		Prefix:      b.Prefix,
+	}
+	cmd.Schedule(ci)
+}
+
 // finds a command in the command map
 //
 // If it exists, it returns the Command
@@ -156,9 +254,24 @@ func (b *Bot) compileCommands() {
 	b.addCommand("unregister", cmd.Unregister)
 	b.addCommand("trade", cmd.Trade)
 	b.addCommand("offer", cmd.Offer)
+	b.addCommand("counter", cmd.Counter)
+	b.addCommand("decline", cmd.Decline)
+	b.addCommand("bid", cmd.Bid)
 	b.addCommand("rep", cmd.Rep)
 	b.addCommand("accept", cmd.Accept)
 	b.addCommand("reject", cmd.Reject)
+	b.addCommand("timezone", cmd.Timezone)
+	b.addCommand("host", cmd.Host)
+	b.addCommand("minrep", cmd.MinRep)
+	b.addCommand("events", cmd.Events)
+	b.addCommand("trades", cmd.Trades)
+	b.addCommand("cohost", cmd.CoHost)
+	b.addCommand("position", cmd.Position)
+	b.addCommand("history", cmd.History)
+	b.addCommand("subscribe", cmd.Subscribe)
+	b.addCommand("unsubscribe", cmd.Unsubscribe)
+	b.addCommand("confirm", cmd.Confirm)
+	b.addCommand("wishlist", cmd.Wishlist)
 }
 
 // utility func to add command to bot command map
@@ -177,3 +290,10 @@ func (b *Bot) addCommand(name string, cmd func(cmd.CommandInfo)) {
 func (b *Bot) SetPrefix(newPrefix string) {
 	b.Prefix = newPrefix
 }
+
+// SetRoutes sets the listing channels and role pings per event type and
+// trade category from .config
+func (b *Bot) SetRoutes(events, trades map[string]cmd.Route) {
+	b.EventRoutes = events
+	b.TradeRoutes = trades
+}
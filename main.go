@@ -29,11 +29,22 @@ func main() {
 		models.WithUsers(),
 		models.WithRep(),
 		models.WithTrades(),
+		models.WithProfiles(),
+		models.WithBlocks(),
+		models.WithMinReps(),
+		models.WithHistory(),
+		models.WithTemplates(),
+		models.WithSubscriptions(),
+		models.WithInteractions(),
+		models.WithAttendance(),
+		models.WithLedger(),
+		models.WithWishlists(),
+		models.WithCatalog(),
 	)
 	if err != nil {
 		fmt.Println(err)
 	}
-	isa, err := isabellebot.New(bc.BotKey, bc.AdminRole, bc.ListingID, bc.BotChID, bc.AppID)
+	isa, err := isabellebot.New(bc.BotKey, bc.AdminRole, bc.ListingID, bc.BotChID, bc.AppID, bc.LogID)
 	if err != nil {
 		fmt.Printf("%s", err)
 		return
@@ -49,9 +60,14 @@ func main() {
 
 	// Set user bot prefix
 	isa.SetPrefix(bc.BotPrefix)
+	// Set listing channels and role pings
+	isa.SetRoutes(bc.Routes.Events, bc.Routes.Trades)
 	// Set cleaning schedule
-	cleaning := scheduleClean(clean, 15*time.Minute, isa)
+	cleaning := scheduleTask(clean, 15*time.Minute, isa)
 	defer cleaning.Stop()
+	// Set timed tasks schedule (scheduled events, reminders)
+	tasks := scheduleTask(schedule, time.Minute, isa)
+	defer tasks.Stop()
 
 	err = isa.DS.Open()
 	if err != nil {
@@ -68,13 +84,20 @@ func main() {
 
 // clean will call the routine cleans for event and user tracking
 func clean(isa *isabellebot.Bot) {
-	isa.Service.Event.Clean()
+	for _, e := range isa.Service.Event.Clean() {
+		isa.Service.History.Archive(e, models.ReasonExpired)
+	}
 	isa.Service.Trade.Clean()
 	isa.Service.User.Clean()
 }
 
-// scheduleClean will run routine cleaning after specified time duration
-func scheduleClean(f func(*isabellebot.Bot), interval time.Duration, isa *isabellebot.Bot) *time.Ticker {
+// schedule will call the bot's timed tasks
+func schedule(isa *isabellebot.Bot) {
+	isa.Schedule()
+}
+
+// scheduleTask will run a routine task after every specified time duration
+func scheduleTask(f func(*isabellebot.Bot), interval time.Duration, isa *isabellebot.Bot) *time.Ticker {
 	ticker := time.NewTicker(interval)
 	go func() {
 		for range ticker.C {
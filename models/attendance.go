@@ -0,0 +1,106 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// attendanceHistory is how many of a user's most recent calls make up
+	// their reliability score
+	attendanceHistory = 20
+)
+
+// Attendance defines the postgres SQL table model of whether called users
+// showed up using GORM
+type Attendance struct {
+	gorm.Model
+
+	// Discord ID of the called user
+	UserID string `gorm:"not_null;index"`
+
+	// Event ID and host the user was called to
+	EventID string `gorm:"not_null"`
+	HostID  string `gorm:"not_null"`
+
+	// Showed is false for no-shows
+	Showed bool
+}
+
+// AttendanceService wraps to AttendanceDB
+type AttendanceService interface {
+	AttendanceDB
+}
+
+// AttendanceDB contains all methods we can use to interact with the
+// attendance database
+type AttendanceDB interface {
+	// Record saves whether a called user showed up
+	Record(attendance *Attendance) error
+
+	// Reliability returns the percentage (0-100) of the user's recent calls
+	// they showed up to along with how many calls it's based on
+	//
+	// Users without attendance history have a score of 100
+	Reliability(userID string) (int, int)
+}
+
+type attendanceGorm struct {
+	// gorm database connection
+	db *gorm.DB
+}
+
+type attendanceService struct {
+	AttendanceDB
+}
+
+type attendanceValidator struct {
+	AttendanceDB
+}
+
+var _ AttendanceDB = &attendanceGorm{}
+
+// NewAttendanceService creates the attendance service object
+func NewAttendanceService(db *gorm.DB) AttendanceService {
+	return &attendanceService{
+		AttendanceDB: &attendanceValidator{
+			AttendanceDB: &attendanceGorm{
+				db: db,
+			},
+		},
+	}
+}
+
+// Record makes sure the user and event are given
+func (av *attendanceValidator) Record(attendance *Attendance) error {
+	if attendance.UserID == "" {
+		return errors.New(ErrDiscordIDRequired)
+	}
+	if attendance.EventID == "" {
+		return errors.New("need event ID")
+	}
+	return av.AttendanceDB.Record(attendance)
+}
+
+// Record saves whether a called user showed up
+func (ag *attendanceGorm) Record(attendance *Attendance) error {
+	return ag.db.Create(attendance).Error
+}
+
+// Reliability returns the percentage (0-100) of the user's recent calls
+// they showed up to along with how many calls it's based on
+func (ag *attendanceGorm) Reliability(userID string) (int, int) {
+	var recent []Attendance
+	ag.db.Where("user_id = ?", userID).Order("created_at desc").Limit(attendanceHistory).Find(&recent)
+	if len(recent) == 0 {
+		return 100, 0
+	}
+	var showed int
+	for _, a := range recent {
+		if a.Showed {
+			showed++
+		}
+	}
+	return showed * 100 / len(recent), len(recent)
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// ErrAlreadyBlocked is returned when a host blocks a user twice
+	ErrAlreadyBlocked string = "user is already blocked"
+
+	// ErrNotBlocked is returned when a host unblocks a user they never blocked
+	ErrNotBlocked string = "user is not blocked"
+)
+
+// Block defines the postgres SQL table model of users a host has
+// blocked from their events using GORM
+type Block struct {
+	gorm.Model
+
+	// Discord ID of the host who blocked the user
+	HostID string `gorm:"not_null;index"`
+
+	// Discord ID of the blocked user
+	UserID string `gorm:"not_null"`
+}
+
+// BlockService wraps to BlockDB
+type BlockService interface {
+	BlockDB
+}
+
+// BlockDB contains all methods we can use to interact with the
+// block database
+type BlockDB interface {
+	// Block stops a user from joining a host's events
+	Block(hostID, userID string) error
+
+	// Unblock lets a user join a host's events again
+	Unblock(hostID, userID string) error
+
+	// Blocked returns true if the host blocked the user
+	Blocked(hostID, userID string) bool
+}
+
+type blockGorm struct {
+	// gorm database connection
+	db *gorm.DB
+}
+
+type blockService struct {
+	BlockDB
+}
+
+type blockValidator struct {
+	BlockDB
+}
+
+var _ BlockDB = &blockGorm{}
+
+// NewBlockService creates the block service object
+func NewBlockService(db *gorm.DB) BlockService {
+	return &blockService{
+		BlockDB: &blockValidator{
+			BlockDB: &blockGorm{
+				db: db,
+			},
+		},
+	}
+}
+
+// Block makes sure both users are given and the user isn't
+// already blocked
+func (bv *blockValidator) Block(hostID, userID string) error {
+	if hostID == "" || userID == "" {
+		return errors.New(ErrDiscordIDRequired)
+	}
+	if hostID == userID {
+		return errors.New("you cannot block yourself")
+	}
+	if bv.BlockDB.Blocked(hostID, userID) {
+		return errors.New(ErrAlreadyBlocked)
+	}
+	return bv.BlockDB.Block(hostID, userID)
+}
+
+// Unblock makes sure the user was blocked before removing the block
+func (bv *blockValidator) Unblock(hostID, userID string) error {
+	if !bv.BlockDB.Blocked(hostID, userID) {
+		return errors.New(ErrNotBlocked)
+	}
+	return bv.BlockDB.Unblock(hostID, userID)
+}
+
+// Block stops a user from joining a host's events
+func (bg *blockGorm) Block(hostID, userID string) error {
+	return bg.db.Create(&Block{HostID: hostID, UserID: userID}).Error
+}
+
+// Unblock lets a user join a host's events again
+func (bg *blockGorm) Unblock(hostID, userID string) error {
+	return bg.db.Unscoped().Where("host_id = ? AND user_id = ?", hostID, userID).Delete(&Block{}).Error
+}
+
+// Blocked returns true if the host blocked the user
+func (bg *blockGorm) Blocked(hostID, userID string) bool {
+	var block Block
+	db := bg.db.Where("host_id = ? AND user_id = ?", hostID, userID)
+	return first(db, &block) == nil
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// Item categories of the catalog
+	CategoryFurniture string = "furniture"
+	CategoryDIY       string = "diy"
+	CategoryMaterial  string = "material"
+	CategoryClothing  string = "clothing"
+	CategoryTicket    string = "ticket"
+)
+
+// Item represents a database entry of a tradeable item in the postgres
+// items catalog
+type Item struct {
+	ID       uint   `gorm:"primary_key"`
+	Name     string `gorm:"type:varchar(255);not null"`
+	Category string `gorm:"type:varchar(255)"`
+
+	// other names the item goes by separated by | (e.g. nmt|miles ticket)
+	Aliases string `gorm:"type:varchar(255)"`
+}
+
+// Names returns the canonical name of the item followed by its aliases
+func (i Item) Names() []string {
+	names := []string{i.Name}
+	for _, a := range strings.Split(i.Aliases, "|") {
+		if a = strings.TrimSpace(a); a != "" {
+			names = append(names, a)
+		}
+	}
+	return names
+}
+
+// CatalogService handles interactions with the items catalog database
+type CatalogService interface {
+	CatalogDB
+}
+
+// CatalogDB is used to interact with the items catalog database
+type CatalogDB interface {
+	// All returns every item in the catalog
+	//
+	// An empty slice means the catalog wasn't set up
+	All() []Item
+}
+
+type catalogGorm struct {
+	db *gorm.DB
+}
+
+type catalogService struct {
+	CatalogDB
+}
+
+// Internal check if we're correctly implementing interface
+var _ CatalogDB = &catalogGorm{}
+
+// NewCatalogService creates a new service to the items catalog database
+func NewCatalogService(db *gorm.DB) CatalogService {
+	return &catalogService{
+		CatalogDB: &catalogGorm{
+			db: db,
+		},
+	}
+}
+
+// All returns every item in the catalog
+func (cg *catalogGorm) All() []Item {
+	var items []Item
+	cg.db.Table("items").Order("name").Find(&items)
+	return items
+}
@@ -2,12 +2,26 @@ package models
 
 import (
 	"errors"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+const (
+	// ErrQueueFull is returned when a user tries to join a queue which
+	// already reached its limit
+	ErrQueueFull string = "queue limit reached"
+
+	// MaxCoHosts limits the amount of co-hosts an event can have
+	MaxCoHosts int = 3
+
+	// ErrNotCalled is returned when a user confirms a visit they weren't
+	// called for
+	ErrNotCalled string = "you haven't been called or already confirmed"
+)
+
 // EventService is a layer of abstraction leading to the Event interface
 type EventService interface {
 	Event
@@ -16,14 +30,45 @@ type EventService interface {
 // Event represents all methods we can use to interact with Event type data
 type Event interface {
 	// AddEvent creates a new event on the server
-	AddEvent(User *discordgo.User, MsgID string, limit int)
+	//
+	// Events with a zero start time open immediately
+	AddEvent(MsgID string, event *EventData)
+
+	// GetEvent returns a copy of an event's data
+	GetEvent(eventID string) EventData
+
+	// All returns copies of every active event sorted by expiration
+	All() []EventData
+
+	// ByType returns copies of every active event of an event type sorted
+	// by expiration
+	ByType(eventType string) []EventData
+
+	// ByMessage returns the ID of the event whose listing is the message
+	ByMessage(messageID string) (string, bool)
+
+	// SetListing records where the event's listing message was posted
+	SetListing(eventID, channelID, messageID string)
+
+	// Subscribe adds a user to the reminder list of an upcoming event
+	Subscribe(User *discordgo.User, eventID string) error
+
+	// Remind returns the IDs of upcoming events which start within the
+	// given duration and have not been reminded yet
+	Remind(within time.Duration) []string
+
+	// OpenDue opens the queues of all upcoming events whose start time
+	// has passed and returns their IDs
+	OpenDue() []string
 
 	// EventExists will check if a requested event exists currently
 	EventExists(msgID string) bool
 
 	// AddToQueue will add another user to the queue who registers as long as the
 	// queue is not full
-	AddToQueue(UserID *discordgo.User, eventID string) (*discordgo.User, error)
+	//
+	// rep is the user's reputation which must meet the event's minimum
+	AddToQueue(UserID *discordgo.User, rep int, eventID string) (*discordgo.User, error)
 
 	// GetQueue will return the current queue line
 	GetQueue(eventID string) *[]QueueUser
@@ -36,10 +81,71 @@ type Event interface {
 	// DO NOT CALL THIS RANDOMLY!!
 	//
 	// This should only be called in the goroutine in main (ticker to check expiration)
-	Clean()
+	//
+	// Copies of the removed events are returned so they can be archived
+	Clean() []EventData
+
+	// Remove will remove a queue or waitlist individual from event based on Event ID
+	//
+	// If a queue spot opens up, the first waitlisted user is promoted and returned
+	Remove(eventID string, user *discordgo.User) *discordgo.User
 
-	// Remove will remove a queue individual from event based on Event ID
-	Remove(eventID string, user *discordgo.User)
+	// AddToWaitlist will add a user to the waitlist of a full queue
+	//
+	// It returns the event host and the user's waitlist position
+	AddToWaitlist(User *discordgo.User, rep int, eventID string) (*discordgo.User, int, error)
+
+	// SetLimit changes the queue limit of an event and returns the waitlisted
+	// users who were promoted into the queue
+	SetLimit(eventID string, limit int) []*discordgo.User
+
+	// SetLocked locks or unlocks an event's queue; locked queues don't accept
+	// new users
+	SetLocked(eventID string, locked bool)
+
+	// Move places a queued user at a new (1 based) position in the queue
+	Move(eventID string, user *discordgo.User, pos int) error
+
+	// AddEntry records a user's entry to a lottery event while its entry
+	// window is open and returns the event host
+	AddEntry(User *discordgo.User, rep int, eventID string) (*discordgo.User, error)
+
+	// DueDraws returns the IDs of lottery events whose entry window closed
+	// but haven't been drawn yet
+	DueDraws() []string
+
+	// SetDraw fills the queue (then waitlist) of a lottery event in the drawn
+	// order and returns the entries who didn't make it
+	SetDraw(eventID string, seed int64, order []QueueUser) []QueueUser
+
+	// AddCoHost adds a co-host to an event
+	AddCoHost(eventID string, user *discordgo.User) error
+
+	// RemoveCoHost removes a co-host from an event
+	RemoveCoHost(eventID, userID string) error
+
+	// IsHost returns true if the user is the host or a co-host of the event
+	IsHost(eventID, userID string) bool
+
+	// Next admits the first user in the queue and returns them along with the
+	// waitlisted user promoted into the freed spot (if any)
+	//
+	// If the event has a confirm window, the user must confirm before it ends
+	Next(eventID string) (*discordgo.User, *discordgo.User, error)
+
+	// Confirm marks a called user as on their way
+	Confirm(eventID, userID string) error
+
+	// NoShows removes called users whose confirm window ended from the
+	// admitted users and returns them
+	NoShows() []NoShow
+
+	// Watch saves the direct message in which a user's queue position is
+	// refreshed
+	Watch(eventID, userID string, w Watcher)
+
+	// Unwatch stops refreshing a user's queue position
+	Unwatch(eventID, userID string)
 
 	// GetHost returns the original host of the event
 	GetHost(eventID string) *discordgo.User
@@ -50,10 +156,82 @@ type Event interface {
 
 // EventData represents an event a user has created
 type EventData struct {
+	// ID of the event (also the queue ID)
+	ID string
+
+	// Type is the event keyword used to create the event (e.g. diy)
+	Type string
+
+	// Name and image of the event type
+	Name string
+	Img  string
+
+	// Custom message set by the host
+	Msg string
+
 	DiscordUser *discordgo.User
 	Limit       int
 	Queue       []QueueUser
-	Expiration  time.Time
+
+	// CoHosts help the host run the event
+	CoHosts []*discordgo.User
+
+	// Admitted are the users the hosts called from the queue
+	Admitted []QueueUser
+
+	// ConfirmWindow is how long called users have to confirm they're coming
+	// before they're skipped as a no-show; 0 disables confirmations
+	ConfirmWindow time.Duration
+
+	// Pending maps called users who haven't confirmed yet to their deadline
+	Pending map[string]time.Time
+
+	// MinReliability is the attendance score (0-100) users with attendance
+	// history need to join the event
+	MinReliability int
+
+	// Departures holds the times users left the queue which is used to
+	// estimate wait times
+	Departures []time.Time
+
+	// Watchers maps user IDs to the direct message their position is shown in
+	Watchers map[string]Watcher
+
+	// MinRep is the reputation users need to join the event
+	MinRep int
+
+	// WaitlistLimit is the max size of the waitlist; 0 disables it
+	WaitlistLimit int
+	Waitlist      []QueueUser
+
+	// Start is the time the queue opens
+	Start      time.Time
+	Expiration time.Time
+
+	// Open is true once the queue accepts users
+	Open bool
+
+	// Locked queues don't accept new users
+	Locked bool
+
+	// Lottery events collect entries during the entry window after the start
+	// and draw the queue order at random once it closes
+	Lottery  bool
+	Window   time.Duration
+	Weighted bool
+	Entries  []QueueUser
+	Drawn    bool
+	Seed     int64
+
+	// Reminded is true once subscribers were reminded of the start
+	Reminded bool
+
+	// Subscribers are reminded before an upcoming event opens
+	Subscribers []*discordgo.User
+
+	// Location of the listing message
+	ChannelID string
+	MessageID string
 }
 
 type eventStore struct {
@@ -70,6 +248,26 @@ type QueueUser struct {
 	DiscordUser *discordgo.User
 }
 
+// NoShow is a called user who didn't confirm in time
+type NoShow struct {
+	EventID string
+	User    *discordgo.User
+
+	// host and name of the event when the user was skipped
+	HostID string
+	Name   string
+}
+
+// Watcher represents the direct message a queued user's position and
+// estimated wait is refreshed in
+type Watcher struct {
+	ChannelID string
+	MessageID string
+
+	// last position shown to the user
+	Position int
+}
+
 // internal check to see if interface is implemented correctly
 var _ Event = &eventStore{}
 
@@ -102,35 +300,191 @@ func (es eventStore) EventExists(msgID string) bool {
 }
 
 // AddEvent creates a new event on the server
-func (es eventStore) AddEvent(User *discordgo.User, MsgID string, limit int) {
+//
+// Events with a zero start time open immediately
+func (es eventStore) AddEvent(MsgID string, event *EventData) {
 	es.m.Lock()
 	defer es.m.Unlock()
-	newQ := make([]QueueUser, 0)
-	new := &EventData{
-		DiscordUser: User,
-		Limit:       limit,
-		Queue:       newQ,
-		Expiration:  time.Now().Add(2 * time.Hour),
+	if event.Start.IsZero() {
+		event.Start = time.Now()
+	}
+	event.ID = MsgID
+	event.Open = !event.Start.After(time.Now())
+	event.Queue = make([]QueueUser, 0)
+	event.Waitlist = make([]QueueUser, 0)
+	event.Entries = make([]QueueUser, 0)
+	event.CoHosts = make([]*discordgo.User, 0)
+	event.Admitted = make([]QueueUser, 0)
+	event.Departures = make([]time.Time, 0)
+	event.Watchers = make(map[string]Watcher)
+	event.Pending = make(map[string]time.Time)
+	event.Subscribers = make([]*discordgo.User, 0)
+	event.Expiration = event.Start.Add(2 * time.Hour)
+	es.eb[MsgID] = event
+}
+
+// GetEvent returns a copy of an event's data
+func (es eventStore) GetEvent(eventID string) EventData {
+	es.m.RLock()
+	defer es.m.RUnlock()
+	val, ok := es.eb[eventID]
+	if !ok {
+		return EventData{}
 	}
-	es.eb[MsgID] = new
+	return copyEvent(val)
+}
+
+// copyEvent returns a copy of an event's data which is safe to use after the
+// lock is released; the caller must hold the lock
+func copyEvent(val *EventData) EventData {
+	ret := *val
+	ret.Queue = append([]QueueUser(nil), val.Queue...)
+	ret.Waitlist = append([]QueueUser(nil), val.Waitlist...)
+	ret.Entries = append([]QueueUser(nil), val.Entries...)
+	ret.CoHosts = append([]*discordgo.User(nil), val.CoHosts...)
+	ret.Admitted = append([]QueueUser(nil), val.Admitted...)
+	ret.Departures = append([]time.Time(nil), val.Departures...)
+	ret.Watchers = make(map[string]Watcher, len(val.Watchers))
+	for k, v := range val.Watchers {
+		ret.Watchers[k] = v
+	}
+	ret.Pending = make(map[string]time.Time, len(val.Pending))
+	for k, v := range val.Pending {
+		ret.Pending[k] = v
+	}
+	ret.Subscribers = append([]*discordgo.User(nil), val.Subscribers...)
+	return ret
+}
+
+// All returns copies of every active event sorted by expiration
+func (es eventStore) All() []EventData {
+	return es.filter(func(e *EventData) bool { return true })
+}
+
+// ByType returns copies of every active event of an event type sorted
+// by expiration
+func (es eventStore) ByType(eventType string) []EventData {
+	return es.filter(func(e *EventData) bool { return e.Type == eventType })
+}
+
+// filter returns copies of the events matching keep sorted by expiration
+func (es eventStore) filter(keep func(*EventData) bool) []EventData {
+	es.m.RLock()
+	var ret []EventData
+	for _, v := range es.eb {
+		if keep(v) {
+			ret = append(ret, copyEvent(v))
+		}
+	}
+	es.m.RUnlock()
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Expiration.Before(ret[j].Expiration)
+	})
+	return ret
+}
+
+// ByMessage returns the ID of the event whose listing is the message
+func (es eventStore) ByMessage(messageID string) (string, bool) {
+	es.m.RLock()
+	defer es.m.RUnlock()
+	for id, e := range es.eb {
+		if e.MessageID == messageID {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// SetListing records where the event's listing message was posted
+func (es eventStore) SetListing(eventID, channelID, messageID string) {
+	es.m.Lock()
+	defer es.m.Unlock()
+	if val, ok := es.eb[eventID]; ok {
+		val.ChannelID = channelID
+		val.MessageID = messageID
+	}
+}
+
+// Subscribe adds a user to the reminder list of an upcoming event
+func (es eventStore) Subscribe(User *discordgo.User, eventID string) error {
+	es.m.Lock()
+	defer es.m.Unlock()
+	val := es.eb[eventID]
+	if val.Open {
+		return errors.New("event is already open")
+	}
+	if isHost(val, User.ID) {
+		return errors.New("you cannot subscribe to your own event")
+	}
+	for _, u := range val.Subscribers {
+		if u.ID == User.ID {
+			return errors.New("user already subscribed")
+		}
+	}
+	val.Subscribers = append(val.Subscribers, User)
+	return nil
+}
+
+// Remind returns the IDs of upcoming events which start within the
+// given duration and have not been reminded yet
+func (es eventStore) Remind(within time.Duration) []string {
+	es.m.Lock()
+	defer es.m.Unlock()
+	var ret []string
+	for k, v := range es.eb {
+		if v.Open || v.Reminded || time.Until(v.Start) > within {
+			continue
+		}
+		v.Reminded = true
+		ret = append(ret, k)
+	}
+	return ret
+}
+
+// OpenDue opens the queues of all upcoming events whose start time
+// has passed and returns their IDs
+func (es eventStore) OpenDue() []string {
+	es.m.Lock()
+	defer es.m.Unlock()
+	var ret []string
+	for k, v := range es.eb {
+		if v.Open || v.Start.After(time.Now()) {
+			continue
+		}
+		v.Open = true
+		ret = append(ret, k)
+	}
+	return ret
 }
 
 // AddToQueue will add another user to the queue who registers as long as the
 // queue is not full
-func (es eventStore) AddToQueue(User *discordgo.User, eventID string) (*discordgo.User, error) {
+//
+// rep is the user's reputation which must meet the event's minimum
+func (es eventStore) AddToQueue(User *discordgo.User, rep int, eventID string) (*discordgo.User, error) {
 	es.m.Lock()
 	defer es.m.Unlock()
 	val := es.eb[eventID]
-	if len(val.Queue) == val.Limit {
-		return nil, errors.New("queue limit reached")
+	if !val.Open {
+		return nil, errors.New("event has not opened yet")
 	}
-	if val.DiscordUser.ID == User.ID {
+	if isHost(val, User.ID) {
 		return nil, errors.New("you cannot queue for your own event")
 	}
-	for _, u := range val.Queue {
-		if u.DiscordUser.ID == User.ID {
-			return nil, errors.New("user already in queue")
-		}
+	if inQueue(User, val.Queue) || inQueue(User, val.Waitlist) {
+		return nil, errors.New("user already in queue")
+	}
+	if val.Locked {
+		return nil, errors.New("the host locked this queue")
+	}
+	if val.Lottery && !val.Drawn {
+		return nil, errors.New("this lottery hasn't been drawn yet")
+	}
+	if err := meetsMinRep(val.MinRep, rep, "event"); err != nil {
+		return nil, err
+	}
+	if len(val.Queue) >= val.Limit {
+		return nil, errors.New(ErrQueueFull)
 	}
 	newUser := QueueUser{
 		DiscordUser: User,
@@ -140,6 +494,320 @@ func (es eventStore) AddToQueue(User *discordgo.User, eventID string) (*discordg
 	return val.DiscordUser, nil
 }
 
+// AddToWaitlist will add a user to the waitlist of a full queue
+//
+// It returns the event host and the user's waitlist position
+func (es eventStore) AddToWaitlist(User *discordgo.User, rep int, eventID string) (*discordgo.User, int, error) {
+	es.m.Lock()
+	defer es.m.Unlock()
+	val := es.eb[eventID]
+	if val.WaitlistLimit == 0 {
+		return nil, 0, errors.New("this event does not have a waitlist")
+	}
+	if len(val.Queue) < val.Limit {
+		return nil, 0, errors.New("the queue still has room")
+	}
+	if isHost(val, User.ID) {
+		return nil, 0, errors.New("you cannot queue for your own event")
+	}
+	if inQueue(User, val.Queue) || inQueue(User, val.Waitlist) {
+		return nil, 0, errors.New("user already in queue")
+	}
+	if val.Locked {
+		return nil, 0, errors.New("the host locked this queue")
+	}
+	if err := meetsMinRep(val.MinRep, rep, "event"); err != nil {
+		return nil, 0, err
+	}
+	if len(val.Waitlist) >= val.WaitlistLimit {
+		return nil, 0, errors.New("queue and waitlist are full")
+	}
+	val.Waitlist = append(val.Waitlist, QueueUser{DiscordUser: User})
+	return val.DiscordUser, len(val.Waitlist), nil
+}
+
+// SetLimit changes the queue limit of an event and returns the waitlisted
+// users who were promoted into the queue
+func (es eventStore) SetLimit(eventID string, limit int) []*discordgo.User {
+	es.m.Lock()
+	defer es.m.Unlock()
+	val := es.eb[eventID]
+	val.Limit = limit
+	return promote(val)
+}
+
+// SetLocked locks or unlocks an event's queue; locked queues don't accept
+// new users
+func (es eventStore) SetLocked(eventID string, locked bool) {
+	es.m.Lock()
+	defer es.m.Unlock()
+	es.eb[eventID].Locked = locked
+}
+
+// Move places a queued user at a new (1 based) position in the queue
+func (es eventStore) Move(eventID string, user *discordgo.User, pos int) error {
+	es.m.Lock()
+	defer es.m.Unlock()
+	val := es.eb[eventID]
+	if !inQueue(user, val.Queue) {
+		return errors.New("user is not in the queue")
+	}
+	if pos < 1 || pos > len(val.Queue) {
+		return errors.New("position is outside of the queue")
+	}
+	var moved QueueUser
+	for _, u := range val.Queue {
+		if u.DiscordUser.ID == user.ID {
+			moved = u
+		}
+	}
+	rest := removeUser(user, val.Queue)
+	queue := make([]QueueUser, 0, len(val.Queue))
+	queue = append(queue, rest[:pos-1]...)
+	queue = append(queue, moved)
+	queue = append(queue, rest[pos-1:]...)
+	val.Queue = queue
+	return nil
+}
+
+// AddEntry records a user's entry to a lottery event while its entry
+// window is open and returns the event host
+func (es eventStore) AddEntry(User *discordgo.User, rep int, eventID string) (*discordgo.User, error) {
+	es.m.Lock()
+	defer es.m.Unlock()
+	val := es.eb[eventID]
+	if !val.Lottery {
+		return nil, errors.New("this event is not a lottery")
+	}
+	if !val.Open {
+		return nil, errors.New("event has not opened yet")
+	}
+	if val.Drawn || time.Now().After(val.Start.Add(val.Window)) {
+		return nil, errors.New("the entry window has closed")
+	}
+	if isHost(val, User.ID) {
+		return nil, errors.New("you cannot enter your own event")
+	}
+	if inQueue(User, val.Entries) {
+		return nil, errors.New("user already entered")
+	}
+	if val.Locked {
+		return nil, errors.New("the host locked this queue")
+	}
+	if err := meetsMinRep(val.MinRep, rep, "event"); err != nil {
+		return nil, err
+	}
+	val.Entries = append(val.Entries, QueueUser{DiscordUser: User})
+	return val.DiscordUser, nil
+}
+
+// DueDraws returns the IDs of lottery events whose entry window closed
+// but haven't been drawn yet
+func (es eventStore) DueDraws() []string {
+	es.m.RLock()
+	defer es.m.RUnlock()
+	var ret []string
+	for k, v := range es.eb {
+		if v.Lottery && v.Open && !v.Drawn && time.Now().After(v.Start.Add(v.Window)) {
+			ret = append(ret, k)
+		}
+	}
+	return ret
+}
+
+// SetDraw fills the queue (then waitlist) of a lottery event in the drawn
+// order and returns the entries who didn't make it
+func (es eventStore) SetDraw(eventID string, seed int64, order []QueueUser) []QueueUser {
+	es.m.Lock()
+	defer es.m.Unlock()
+	val := es.eb[eventID]
+	val.Drawn = true
+	val.Seed = seed
+	var rest []QueueUser
+	for _, u := range order {
+		switch {
+		case len(val.Queue) < val.Limit:
+			val.Queue = append(val.Queue, u)
+		case len(val.Waitlist) < val.WaitlistLimit:
+			val.Waitlist = append(val.Waitlist, u)
+		default:
+			rest = append(rest, u)
+		}
+	}
+	return rest
+}
+
+// AddCoHost adds a co-host to an event
+func (es eventStore) AddCoHost(eventID string, user *discordgo.User) error {
+	es.m.Lock()
+	defer es.m.Unlock()
+	val := es.eb[eventID]
+	if isHost(val, user.ID) {
+		return errors.New("user already hosts this event")
+	}
+	if inQueue(user, val.Queue) || inQueue(user, val.Waitlist) || inQueue(user, val.Entries) {
+		return errors.New("user is queued for this event")
+	}
+	if len(val.CoHosts) >= MaxCoHosts {
+		return errors.New("this event already has the max amount of co-hosts")
+	}
+	val.CoHosts = append(val.CoHosts, user)
+	return nil
+}
+
+// RemoveCoHost removes a co-host from an event
+func (es eventStore) RemoveCoHost(eventID, userID string) error {
+	es.m.Lock()
+	defer es.m.Unlock()
+	val := es.eb[eventID]
+	var ret []*discordgo.User
+	for _, u := range val.CoHosts {
+		if u.ID == userID {
+			continue
+		}
+		ret = append(ret, u)
+	}
+	if len(ret) == len(val.CoHosts) {
+		return errors.New("user is not a co-host of this event")
+	}
+	val.CoHosts = ret
+	return nil
+}
+
+// IsHost returns true if the user is the host or a co-host of the event
+func (es eventStore) IsHost(eventID, userID string) bool {
+	es.m.RLock()
+	defer es.m.RUnlock()
+	val, ok := es.eb[eventID]
+	if !ok {
+		return false
+	}
+	return isHost(val, userID)
+}
+
+// isHost returns true if the user is the host or a co-host of the event
+//
+// The caller must hold the lock
+func isHost(val *EventData, userID string) bool {
+	if val.DiscordUser.ID == userID {
+		return true
+	}
+	for _, u := range val.CoHosts {
+		if u.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Next admits the first user in the queue and returns them along with the
+// waitlisted user promoted into the freed spot (if any)
+func (es eventStore) Next(eventID string) (*discordgo.User, *discordgo.User, error) {
+	es.m.Lock()
+	defer es.m.Unlock()
+	val := es.eb[eventID]
+	if len(val.Queue) == 0 {
+		return nil, nil, errors.New("the queue is empty")
+	}
+	next := val.Queue[0]
+	val.Queue = val.Queue[1:]
+	val.Admitted = append(val.Admitted, next)
+	val.Departures = append(val.Departures, time.Now())
+	if val.ConfirmWindow > 0 {
+		val.Pending[next.DiscordUser.ID] = time.Now().Add(val.ConfirmWindow)
+	}
+	var promoted *discordgo.User
+	if p := promote(val); len(p) > 0 {
+		promoted = p[0]
+	}
+	return next.DiscordUser, promoted, nil
+}
+
+// Confirm marks a called user as on their way
+func (es eventStore) Confirm(eventID, userID string) error {
+	es.m.Lock()
+	defer es.m.Unlock()
+	val, ok := es.eb[eventID]
+	if !ok {
+		return errors.New("event not found")
+	}
+	if _, ok := val.Pending[userID]; !ok {
+		return errors.New(ErrNotCalled)
+	}
+	delete(val.Pending, userID)
+	return nil
+}
+
+// NoShows removes called users whose confirm window ended from the
+// admitted users and returns them
+func (es eventStore) NoShows() []NoShow {
+	es.m.Lock()
+	defer es.m.Unlock()
+	var ret []NoShow
+	now := time.Now()
+	for id, val := range es.eb {
+		for userID, deadline := range val.Pending {
+			if now.Before(deadline) {
+				continue
+			}
+			delete(val.Pending, userID)
+			user := &discordgo.User{ID: userID}
+			for _, u := range val.Admitted {
+				if u.DiscordUser.ID == userID {
+					user = u.DiscordUser
+				}
+			}
+			val.Admitted = removeUser(user, val.Admitted)
+			ret = append(ret, NoShow{EventID: id, User: user, HostID: val.DiscordUser.ID, Name: val.Name})
+		}
+	}
+	return ret
+}
+
+// Watch saves the direct message in which a user's queue position is
+// refreshed
+func (es eventStore) Watch(eventID, userID string, w Watcher) {
+	es.m.Lock()
+	defer es.m.Unlock()
+	if val, ok := es.eb[eventID]; ok {
+		val.Watchers[userID] = w
+	}
+}
+
+// Unwatch stops refreshing a user's queue position
+func (es eventStore) Unwatch(eventID, userID string) {
+	es.m.Lock()
+	defer es.m.Unlock()
+	if val, ok := es.eb[eventID]; ok {
+		delete(val.Watchers, userID)
+	}
+}
+
+// promote moves waitlisted users into the queue while there is room
+// and returns them
+//
+// The caller must hold the lock
+func promote(val *EventData) []*discordgo.User {
+	var ret []*discordgo.User
+	for len(val.Queue) < val.Limit && len(val.Waitlist) > 0 {
+		next := val.Waitlist[0]
+		val.Waitlist = val.Waitlist[1:]
+		val.Queue = append(val.Queue, next)
+		ret = append(ret, next.DiscordUser)
+	}
+	return ret
+}
+
+// inQueue returns true if the user is found in the queue slice
+func inQueue(user *discordgo.User, queue []QueueUser) bool {
+	for _, u := range queue {
+		if u.DiscordUser.ID == user.ID {
+			return true
+		}
+	}
+	return false
+}
+
 // GetQueue will return the current queue line
 func (es eventStore) GetQueue(eventID string) *[]QueueUser {
 	es.m.RLock()
@@ -152,7 +820,7 @@ func (es eventStore) GetQueue(eventID string) *[]QueueUser {
 func (es eventStore) Close(eventID, role string, user *discordgo.User, roles []string) error {
 	es.m.Lock()
 	defer es.m.Unlock()
-	if !containsRole(role, roles) && es.eb[eventID].DiscordUser.ID != user.ID {
+	if !containsRole(role, roles) && !isHost(es.eb[eventID], user.ID) {
 		return errors.New("permission denied")
 	}
 	delete(es.eb, eventID)
@@ -170,11 +838,23 @@ func containsRole(item string, container []string) bool {
 	return false
 }
 
-// Remove will remove a queue individual from event based on Event ID
-func (es eventStore) Remove(eventID string, user *discordgo.User) {
+// Remove will remove a queue or waitlist individual from event based on Event ID
+//
+// If a queue spot opens up, the first waitlisted user is promoted and returned
+func (es eventStore) Remove(eventID string, user *discordgo.User) *discordgo.User {
 	es.m.Lock()
 	defer es.m.Unlock()
-	es.eb[eventID].Queue = removeUser(user, es.eb[eventID].Queue)
+	val := es.eb[eventID]
+	if inQueue(user, val.Queue) {
+		val.Departures = append(val.Departures, time.Now())
+	}
+	val.Queue = removeUser(user, val.Queue)
+	val.Waitlist = removeUser(user, val.Waitlist)
+	val.Entries = removeUser(user, val.Entries)
+	if promoted := promote(val); len(promoted) > 0 {
+		return promoted[0]
+	}
+	return nil
 }
 
 // removeUser rebuilds the QueueUser slice without the user wanting
@@ -195,14 +875,17 @@ func removeUser(rUser *discordgo.User, users []QueueUser) []QueueUser {
 // DO NOT CALL THIS RANDOMLY!!
 //
 // This should only be called in the goroutine in main (ticker to check expiration)
-func (es eventStore) Clean() {
+func (es eventStore) Clean() []EventData {
 	es.m.Lock()
 	defer es.m.Unlock()
+	var removed []EventData
 	for k, v := range es.eb {
 		if time.Now().Sub(v.Expiration) > 0 {
+			removed = append(removed, *v)
 			delete(es.eb, k)
 		}
 	}
+	return removed
 }
 
 // NewEventService creates a new Event service
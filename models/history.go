@@ -0,0 +1,209 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// ReasonHost is recorded when a host or co-host closes their event
+	ReasonHost string = "host"
+
+	// ReasonMod is recorded when a moderator closes an event
+	ReasonMod string = "mod"
+
+	// ReasonExpired is recorded when an event is cleaned after it expired
+	ReasonExpired string = "expired"
+)
+
+// EventRecord defines the postgres SQL table model of closed and expired
+// events using GORM
+type EventRecord struct {
+	gorm.Model
+
+	// Event ID the event had while it was listed
+	EventID string `gorm:"not_null"`
+
+	// Event keyword (e.g. celeste)
+	EventType string `gorm:"not_null;index"`
+
+	// Event display name
+	Name string
+
+	// Host discord ID
+	HostID string `gorm:"not_null;index"`
+
+	// Queue limit
+	Limit int
+
+	// Number of visitors the hosts called from the queue
+	Visitors int
+
+	// Times the queue opened and the event ended
+	Opened time.Time
+	Ended  time.Time
+
+	// Why the event ended (host, mod or expired)
+	Reason string `gorm:"not_null"`
+}
+
+// Visit defines the postgres SQL table model of users admitted to events
+// using GORM
+type Visit struct {
+	gorm.Model
+
+	// Archived event the visit belongs to
+	EventRecordID uint `gorm:"not_null;index"`
+
+	// Visitor discord ID
+	UserID string `gorm:"not_null;index"`
+
+	// Host discord ID
+	HostID string `gorm:"not_null"`
+
+	// Event keyword (e.g. celeste)
+	EventType string `gorm:"not_null"`
+}
+
+// HostStat is the number of events a host finished for an event type
+type HostStat struct {
+	HostID string
+	Events int
+}
+
+// HistoryService wraps to HistoryDB
+type HistoryService interface {
+	HistoryDB
+}
+
+// HistoryDB contains all methods we can use to interact with the
+// event history database
+type HistoryDB interface {
+	// Archive records a closed or expired event along with its visitors
+	Archive(event EventData, reason string) error
+
+	// Hosted returns the most recent events a user hosted
+	Hosted(userID string, limit int) []EventRecord
+
+	// Visits returns the most recent events a user visited
+	Visits(userID string, limit int) []Visit
+
+	// Counts returns the total number of events a user hosted and visited
+	Counts(userID string) (int, int)
+
+	// TopHosts returns the hosts with the most events of an event type
+	TopHosts(eventType string, limit int) []HostStat
+}
+
+type historyGorm struct {
+	// gorm database connection
+	db *gorm.DB
+}
+
+type historyService struct {
+	HistoryDB
+}
+
+type historyValidator struct {
+	HistoryDB
+}
+
+var _ HistoryDB = &historyGorm{}
+
+// NewHistoryService creates the event history service object
+func NewHistoryService(db *gorm.DB) HistoryService {
+	return &historyService{
+		HistoryDB: &historyValidator{
+			HistoryDB: &historyGorm{
+				db: db,
+			},
+		},
+	}
+}
+
+// Archive makes sure the event has a host and a known close reason
+func (hv *historyValidator) Archive(event EventData, reason string) error {
+	if event.DiscordUser == nil {
+		return errors.New("need event host")
+	}
+	switch reason {
+	case ReasonHost, ReasonMod, ReasonExpired:
+	default:
+		return errors.New("unknown close reason")
+	}
+	return hv.HistoryDB.Archive(event, reason)
+}
+
+// Archive records a closed or expired event along with its visitors
+func (hg *historyGorm) Archive(event EventData, reason string) error {
+	end := time.Now()
+	if reason == ReasonExpired {
+		end = event.Expiration
+	}
+	record := EventRecord{
+		EventID:   event.ID,
+		EventType: event.Type,
+		Name:      event.Name,
+		HostID:    event.DiscordUser.ID,
+		Limit:     event.Limit,
+		Visitors:  len(event.Admitted),
+		Opened:    event.Start,
+		Ended:     end,
+		Reason:    reason,
+	}
+	tx := hg.db.Begin()
+	if err := tx.Create(&record).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, u := range event.Admitted {
+		visit := Visit{
+			EventRecordID: record.ID,
+			UserID:        u.DiscordUser.ID,
+			HostID:        record.HostID,
+			EventType:     record.EventType,
+		}
+		if err := tx.Create(&visit).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+// Hosted returns the most recent events a user hosted
+func (hg *historyGorm) Hosted(userID string, limit int) []EventRecord {
+	var ret []EventRecord
+	hg.db.Where("host_id = ?", userID).Order("ended desc").Limit(limit).Find(&ret)
+	return ret
+}
+
+// Visits returns the most recent events a user visited
+func (hg *historyGorm) Visits(userID string, limit int) []Visit {
+	var ret []Visit
+	hg.db.Where("user_id = ?", userID).Order("created_at desc").Limit(limit).Find(&ret)
+	return ret
+}
+
+// Counts returns the total number of events a user hosted and visited
+func (hg *historyGorm) Counts(userID string) (int, int) {
+	var hosted, visited int
+	hg.db.Model(&EventRecord{}).Where("host_id = ?", userID).Count(&hosted)
+	hg.db.Model(&Visit{}).Where("user_id = ?", userID).Count(&visited)
+	return hosted, visited
+}
+
+// TopHosts returns the hosts with the most events of an event type
+func (hg *historyGorm) TopHosts(eventType string, limit int) []HostStat {
+	var ret []HostStat
+	hg.db.Model(&EventRecord{}).
+		Select("host_id, count(*) as events").
+		Where("event_type = ?", eventType).
+		Group("host_id").
+		Order("events desc").
+		Limit(limit).
+		Scan(&ret)
+	return ret
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// InteractionVisit is recorded when a host marks a queue member as visited
+	InteractionVisit string = "visit"
+
+	// InteractionTrade is recorded when a trade host completes a trade
+	InteractionTrade string = "trade"
+
+	// ErrNoInteraction is returned when two users have no confirmed
+	// interaction for a listing ID
+	ErrNoInteraction string = "there's no confirmed visit or trade between you two with that ID"
+
+	// ErrAlreadyNominated is returned when a user nominates the other side of
+	// an interaction twice
+	ErrAlreadyNominated string = "you already filed a rep application for this interaction"
+
+	// nominationWindow is how long after an interaction nominations can be filed
+	nominationWindow = 7 * 24 * time.Hour
+)
+
+// Interaction defines the postgres SQL table model of confirmed visits and
+// trades that unlock reputation nominations using GORM
+type Interaction struct {
+	gorm.Model
+
+	// What kind of interaction (visit or trade)
+	Kind string `gorm:"not_null"`
+
+	// Event or trade ID of the interaction
+	ListingID string `gorm:"not_null;index"`
+
+	// Event name or trade item
+	Name string
+
+	// Discord ID of the event or trade host
+	HostID string `gorm:"not_null"`
+
+	// Discord ID of the visitor or trade partner
+	UserID string `gorm:"not_null"`
+
+	// Whether each side already nominated the other
+	HostNominated bool
+	UserNominated bool
+}
+
+// InteractionService wraps to InteractionDB
+type InteractionService interface {
+	InteractionDB
+}
+
+// InteractionDB contains all methods we can use to interact with the
+// interaction database
+type InteractionDB interface {
+	// Record saves a confirmed interaction between a host and a user
+	Record(interaction *Interaction) error
+
+	// Find returns the most recent interaction of a listing ID between two
+	// users in either order
+	Find(listingID, userA, userB string) (*Interaction, error)
+
+	// Nominate marks that a side of the interaction filed a rep application
+	Nominate(interaction *Interaction, fromID string) error
+}
+
+type interactionGorm struct {
+	// gorm database connection
+	db *gorm.DB
+}
+
+type interactionService struct {
+	InteractionDB
+}
+
+type interactionValidator struct {
+	InteractionDB
+}
+
+var _ InteractionDB = &interactionGorm{}
+
+// NewInteractionService creates the interaction service object
+func NewInteractionService(db *gorm.DB) InteractionService {
+	return &interactionService{
+		InteractionDB: &interactionValidator{
+			InteractionDB: &interactionGorm{
+				db: db,
+			},
+		},
+	}
+}
+
+// Record makes sure both sides are given and the interaction wasn't
+// already confirmed
+func (iv *interactionValidator) Record(interaction *Interaction) error {
+	if interaction.HostID == "" || interaction.UserID == "" {
+		return errors.New(ErrDiscordIDRequired)
+	}
+	if interaction.HostID == interaction.UserID {
+		return errors.New("you cannot confirm an interaction with yourself")
+	}
+	if _, err := iv.InteractionDB.Find(interaction.ListingID, interaction.HostID, interaction.UserID); err == nil {
+		return errors.New("this interaction is already confirmed")
+	}
+	return iv.InteractionDB.Record(interaction)
+}
+
+// Nominate makes sure the user is part of the interaction and hasn't
+// nominated the other side yet
+func (iv *interactionValidator) Nominate(interaction *Interaction, fromID string) error {
+	switch fromID {
+	case interaction.HostID:
+		if interaction.HostNominated {
+			return errors.New(ErrAlreadyNominated)
+		}
+	case interaction.UserID:
+		if interaction.UserNominated {
+			return errors.New(ErrAlreadyNominated)
+		}
+	default:
+		return errors.New(ErrNoInteraction)
+	}
+	return iv.InteractionDB.Nominate(interaction, fromID)
+}
+
+// Record saves a confirmed interaction between a host and a user
+func (ig *interactionGorm) Record(interaction *Interaction) error {
+	return ig.db.Create(interaction).Error
+}
+
+// Find returns the most recent interaction of a listing ID between two
+// users in either order
+//
+// Listing IDs are reused so only recent interactions are considered
+func (ig *interactionGorm) Find(listingID, userA, userB string) (*Interaction, error) {
+	var interaction Interaction
+	db := ig.db.Where("listing_id = ? AND created_at > ? AND "+
+		"((host_id = ? AND user_id = ?) OR (host_id = ? AND user_id = ?))",
+		listingID, time.Now().Add(-nominationWindow), userA, userB, userB, userA).
+		Order("created_at desc")
+	if err := first(db, &interaction); err != nil {
+		if err.Error() == ErrNotFound {
+			return nil, errors.New(ErrNoInteraction)
+		}
+		return nil, err
+	}
+	return &interaction, nil
+}
+
+// Nominate marks that a side of the interaction filed a rep application
+func (ig *interactionGorm) Nominate(interaction *Interaction, fromID string) error {
+	if fromID == interaction.HostID {
+		interaction.HostNominated = true
+	} else {
+		interaction.UserNominated = true
+	}
+	return ig.db.Save(interaction).Error
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/jinzhu/gorm"
+)
+
+// TradeRecord defines the postgres SQL table model of completed trades
+// using GORM
+type TradeRecord struct {
+	gorm.Model
+
+	// Trade ID the trade had while it was listed
+	TradeID string `gorm:"not_null;index"`
+
+	// Item the host traded
+	Item string `gorm:"not_null"`
+
+	// Trade category (empty if the trade had none)
+	Category string
+
+	// Offer the host accepted
+	Offer string
+
+	// Sealed offers are only shown to both sides and mods
+	Sealed bool
+
+	// Host discord ID
+	HostID string `gorm:"not_null;index"`
+
+	// Discord ID of the user whose offer was accepted
+	UserID string `gorm:"not_null;index"`
+}
+
+// LedgerService wraps to LedgerDB
+type LedgerService interface {
+	LedgerDB
+}
+
+// LedgerDB contains all methods we can use to interact with the
+// completed trade database
+type LedgerDB interface {
+	// Record saves a completed trade
+	Record(record *TradeRecord) error
+
+	// ByUser returns every completed trade a user took part in as either
+	// the host or the accepted offerer, newest first
+	ByUser(userID string) []TradeRecord
+
+	// ByTradeID returns every completed trade listed under a trade ID,
+	// newest first
+	//
+	// Trade IDs are reused after a trade closes so there may be more than one
+	ByTradeID(tradeID string) []TradeRecord
+}
+
+type ledgerGorm struct {
+	// gorm database connection
+	db *gorm.DB
+}
+
+type ledgerService struct {
+	LedgerDB
+}
+
+type ledgerValidator struct {
+	LedgerDB
+}
+
+var _ LedgerDB = &ledgerGorm{}
+
+// NewLedgerService creates the trade ledger service object
+func NewLedgerService(db *gorm.DB) LedgerService {
+	return &ledgerService{
+		LedgerDB: &ledgerValidator{
+			LedgerDB: &ledgerGorm{
+				db: db,
+			},
+		},
+	}
+}
+
+// Record makes sure the trade has an ID, an item and both parties
+func (lv *ledgerValidator) Record(record *TradeRecord) error {
+	if record.TradeID == "" || record.Item == "" {
+		return errors.New("need trade ID and item")
+	}
+	if record.HostID == "" || record.UserID == "" {
+		return errors.New(ErrDiscordIDRequired)
+	}
+	return lv.LedgerDB.Record(record)
+}
+
+// Record saves a completed trade
+func (lg *ledgerGorm) Record(record *TradeRecord) error {
+	return lg.db.Create(record).Error
+}
+
+// ByUser returns every completed trade a user took part in, newest first
+func (lg *ledgerGorm) ByUser(userID string) []TradeRecord {
+	var ret []TradeRecord
+	lg.db.Where("host_id = ? OR user_id = ?", userID, userID).Order("created_at desc").Find(&ret)
+	return ret
+}
+
+// ByTradeID returns every completed trade listed under a trade ID, newest first
+func (lg *ledgerGorm) ByTradeID(tradeID string) []TradeRecord {
+	var ret []TradeRecord
+	lg.db.Where("trade_id = ?", tradeID).Order("created_at desc").Find(&ret)
+	return ret
+}
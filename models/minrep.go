@@ -0,0 +1,99 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/jinzhu/gorm"
+)
+
+// MinRep defines the postgres SQL table model of server wide minimum
+// reputation per event type using GORM
+type MinRep struct {
+	gorm.Model
+
+	// Event keyword (e.g. celeste)
+	EventType string `gorm:"not_null;unique_index"`
+
+	// Reputation needed to join events of this type
+	Min int `gorm:"not_null"`
+}
+
+// MinRepService wraps to MinRepDB
+type MinRepService interface {
+	MinRepDB
+}
+
+// MinRepDB contains all methods we can use to interact with the
+// minimum reputation database
+type MinRepDB interface {
+	// Get returns the minimum reputation for an event type (0 if unset)
+	Get(eventType string) int
+
+	// Set saves the minimum reputation for an event type
+	Set(eventType string, min int) error
+
+	// All returns every event type minimum
+	All() []MinRep
+}
+
+type minRepGorm struct {
+	// gorm database connection
+	db *gorm.DB
+}
+
+type minRepService struct {
+	MinRepDB
+}
+
+type minRepValidator struct {
+	MinRepDB
+}
+
+var _ MinRepDB = &minRepGorm{}
+
+// NewMinRepService creates the minimum reputation service object
+func NewMinRepService(db *gorm.DB) MinRepService {
+	return &minRepService{
+		MinRepDB: &minRepValidator{
+			MinRepDB: &minRepGorm{
+				db: db,
+			},
+		},
+	}
+}
+
+// Set makes sure the event type is given and the minimum isn't negative
+func (mv *minRepValidator) Set(eventType string, min int) error {
+	if eventType == "" {
+		return errors.New("need event type")
+	}
+	if min < 0 {
+		return errors.New("minimum reputation can't be negative")
+	}
+	return mv.MinRepDB.Set(eventType, min)
+}
+
+// Get returns the minimum reputation for an event type (0 if unset)
+func (mg *minRepGorm) Get(eventType string) int {
+	var mr MinRep
+	db := mg.db.Where("event_type = ?", eventType)
+	if err := first(db, &mr); err != nil {
+		return 0
+	}
+	return mr.Min
+}
+
+// Set saves the minimum reputation for an event type
+func (mg *minRepGorm) Set(eventType string, min int) error {
+	var mr MinRep
+	return mg.db.Where(MinRep{EventType: eventType}).
+		Assign(MinRep{Min: min}).
+		FirstOrCreate(&mr).Error
+}
+
+// All returns every event type minimum
+func (mg *minRepGorm) All() []MinRep {
+	var ret []MinRep
+	mg.db.Order("event_type").Find(&ret)
+	return ret
+}
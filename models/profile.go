@@ -0,0 +1,154 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// ErrInvalidTimezone is returned when a timezone name can't be
+	// loaded from the IANA database
+	ErrInvalidTimezone string = "invalid timezone; use a name such as America/New_York"
+)
+
+// Profile defines the postgres SQL table model of per user
+// settings using GORM
+type Profile struct {
+	gorm.Model
+
+	// Unique Discord ID
+	DiscordID string `gorm:"not_null;unique_index"`
+
+	// IANA timezone name (e.g. America/New_York)
+	Timezone string
+
+	// Optional quiet hours (15:04) in the user's timezone when subscription
+	// messages aren't sent; empty means no quiet hours
+	QuietStart string
+	QuietEnd   string
+}
+
+// ProfileService wraps to ProfileDB
+type ProfileService interface {
+	ProfileDB
+}
+
+// ProfileDB contains all methods we can use to interact with the
+// profile database
+type ProfileDB interface {
+	// Location returns the user's timezone location
+	//
+	// Users who never set a timezone are treated as UTC
+	Location(userID string) *time.Location
+
+	// SetTimezone saves the user's timezone
+	SetTimezone(userID, timezone string) error
+
+	// QuietHours returns the start and end of the user's quiet hours; both
+	// are empty if the user has none
+	QuietHours(userID string) (string, string)
+
+	// SetQuietHours saves the user's quiet hours; empty times turn them off
+	SetQuietHours(userID, start, end string) error
+}
+
+type profileGorm struct {
+	// gorm database connection
+	db *gorm.DB
+}
+
+type profileService struct {
+	ProfileDB
+}
+
+type profileValidator struct {
+	ProfileDB
+}
+
+var _ ProfileDB = &profileGorm{}
+
+// NewProfileService creates the profile service object
+func NewProfileService(db *gorm.DB) ProfileService {
+	return &profileService{
+		ProfileDB: &profileValidator{
+			ProfileDB: &profileGorm{
+				db: db,
+			},
+		},
+	}
+}
+
+// SetTimezone makes sure the timezone exists before saving it
+func (pv *profileValidator) SetTimezone(userID, timezone string) error {
+	if userID == "" {
+		return errors.New(ErrDiscordIDRequired)
+	}
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "" {
+		return errors.New(ErrInvalidTimezone)
+	}
+	return pv.ProfileDB.SetTimezone(userID, timezone)
+}
+
+// SetQuietHours makes sure both times are either set or empty and look
+// like 22:00
+func (pv *profileValidator) SetQuietHours(userID, start, end string) error {
+	if userID == "" {
+		return errors.New(ErrDiscordIDRequired)
+	}
+	if (start == "") != (end == "") {
+		return errors.New("need both a start and end time")
+	}
+	if start != "" {
+		if _, err := time.Parse("15:04", start); err != nil {
+			return errors.New("quiet hours must look like 22:00")
+		}
+		if _, err := time.Parse("15:04", end); err != nil {
+			return errors.New("quiet hours must look like 22:00")
+		}
+	}
+	return pv.ProfileDB.SetQuietHours(userID, start, end)
+}
+
+// Location returns the user's timezone location
+//
+// Users who never set a timezone are treated as UTC
+func (pg *profileGorm) Location(userID string) *time.Location {
+	var profile Profile
+	db := pg.db.Where("discord_id = ?", userID)
+	if err := first(db, &profile); err != nil {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(profile.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// SetTimezone saves the user's timezone
+func (pg *profileGorm) SetTimezone(userID, timezone string) error {
+	var profile Profile
+	return pg.db.Where(Profile{DiscordID: userID}).
+		Assign(Profile{Timezone: timezone}).
+		FirstOrCreate(&profile).Error
+}
+
+// QuietHours returns the start and end of the user's quiet hours
+func (pg *profileGorm) QuietHours(userID string) (string, string) {
+	var profile Profile
+	db := pg.db.Where("discord_id = ?", userID)
+	if err := first(db, &profile); err != nil {
+		return "", ""
+	}
+	return profile.QuietStart, profile.QuietEnd
+}
+
+// SetQuietHours saves the user's quiet hours
+func (pg *profileGorm) SetQuietHours(userID, start, end string) error {
+	var profile Profile
+	return pg.db.Where(Profile{DiscordID: userID}).
+		Assign(map[string]interface{}{"quiet_start": start, "quiet_end": end}).
+		FirstOrCreate(&profile).Error
+}
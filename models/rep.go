@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/jinzhu/gorm"
@@ -34,8 +35,8 @@ type RepService interface {
 // database
 type RepDB interface {
 	// AddRep adds a repID linked with a user ID to be repped
-	// into a temp map
-	AddRep(userID, repID string)
+	// into a temp map along with the interaction it came from
+	AddRep(userID, repID, source string)
 
 	// Clean will delete a repID event from the tmp map
 	Clean(repID string)
@@ -57,6 +58,10 @@ type RepDB interface {
 	// saved in tmp map
 	GetUser(repID string) string
 
+	// GetSource will return the interaction behind the repID event
+	// saved in tmp map
+	GetSource(repID string) string
+
 	// Increase will increase the rep number in the database for a given
 	// user by one
 	Increase(userID string) error
@@ -66,13 +71,22 @@ type repGorm struct {
 	// gorm database connection
 	db *gorm.DB
 
-	// map is stored by repID -> discordUserID (user to be repped)
-	tmpReps map[string]string
+	// map is stored by repID -> application (user to be repped)
+	tmpReps map[string]repApp
 
 	// mutex lock
 	m *sync.RWMutex
 }
 
+// repApp is a pending rep application
+type repApp struct {
+	// discord ID of the user to be repped
+	userID string
+
+	// interaction the application came from
+	source string
+}
+
 type repService struct {
 	RepDB
 }
@@ -108,7 +122,15 @@ func (rg repGorm) Increase(userID string) error {
 func (rg repGorm) GetUser(repID string) string {
 	rg.m.RLock()
 	defer rg.m.RUnlock()
-	return rg.tmpReps[repID]
+	return rg.tmpReps[repID].userID
+}
+
+// GetSource will return the interaction behind the repID event
+// saved in tmp map
+func (rg repGorm) GetSource(repID string) string {
+	rg.m.RLock()
+	defer rg.m.RUnlock()
+	return rg.tmpReps[repID].source
 }
 
 // RepIDExists returns true if a given RepID event exists in
@@ -123,11 +145,24 @@ func (rg repGorm) RepIDExists(repID string) bool {
 }
 
 // AddRep adds a repID linked with a user ID to be repped
-// into a temp map
-func (rg repGorm) AddRep(userID, repID string) {
+// into a temp map along with the interaction it came from
+func (rg repGorm) AddRep(userID, repID, source string) {
 	rg.m.Lock()
 	defer rg.m.Unlock()
-	rg.tmpReps[repID] = userID
+	rg.tmpReps[repID] = repApp{userID: userID, source: source}
+}
+
+// meetsMinRep returns an error explaining the requirement if a user's
+// reputation is below the minimum of an event or trade
+func meetsMinRep(min, rep int, kind string) error {
+	if rep < 0 {
+		rep = 0
+	}
+	if rep < min {
+		return fmt.Errorf("this %s requires at least %d reputation and you have %d; "+
+			"reputation is earned through accepted rep applications", kind, min, rep)
+	}
+	return nil
 }
 
 // NewRepService creates the rep service object
@@ -136,7 +171,7 @@ func NewRepService(db *gorm.DB) RepService {
 		RepDB: &repValidator{
 			RepDB: &repGorm{
 				db:      db,
-				tmpReps: make(map[string]string),
+				tmpReps: make(map[string]repApp),
 				m:       &sync.RWMutex{},
 			},
 		},
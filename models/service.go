@@ -25,6 +25,39 @@ type Services struct {
 
 	// Gateway to TradeService methods
 	Trade TradeService
+
+	// Gateway to ProfileService methods
+	Profile ProfileService
+
+	// Gateway to BlockService methods
+	Block BlockService
+
+	// Gateway to MinRepService methods
+	MinRep MinRepService
+
+	// Gateway to HistoryService methods
+	History HistoryService
+
+	// Gateway to TemplateService methods
+	Template TemplateService
+
+	// Gateway to SubscriptionService methods
+	Subscription SubscriptionService
+
+	// Gateway to InteractionService methods
+	Interaction InteractionService
+
+	// Gateway to AttendanceService methods
+	Attendance AttendanceService
+
+	// Gateway to LedgerService methods
+	Ledger LedgerService
+
+	// Gateway to WishlistService methods
+	Wishlist WishlistService
+
+	// Gateway to CatalogService methods
+	Catalog CatalogService
 }
 
 // ServicesConfig represents functions that are meant to be running configurations
@@ -95,6 +128,94 @@ func WithTrades() ServicesConfig {
 	}
 }
 
+// WithProfiles will initialize the Profile service
+func WithProfiles() ServicesConfig {
+	return func(s *Services) error {
+		s.Profile = NewProfileService(s.db)
+		return nil
+	}
+}
+
+// WithBlocks will initialize the Block service
+func WithBlocks() ServicesConfig {
+	return func(s *Services) error {
+		s.Block = NewBlockService(s.db)
+		return nil
+	}
+}
+
+// WithMinReps will initialize the MinRep service
+func WithMinReps() ServicesConfig {
+	return func(s *Services) error {
+		s.MinRep = NewMinRepService(s.db)
+		return nil
+	}
+}
+
+// WithHistory will initialize the event History service
+func WithHistory() ServicesConfig {
+	return func(s *Services) error {
+		s.History = NewHistoryService(s.db)
+		return nil
+	}
+}
+
+// WithTemplates will initialize the event Template service
+func WithTemplates() ServicesConfig {
+	return func(s *Services) error {
+		s.Template = NewTemplateService(s.db)
+		return nil
+	}
+}
+
+// WithSubscriptions will initialize the listing Subscription service
+func WithSubscriptions() ServicesConfig {
+	return func(s *Services) error {
+		s.Subscription = NewSubscriptionService(s.db)
+		return nil
+	}
+}
+
+// WithInteractions will initialize the visit and trade Interaction service
+func WithInteractions() ServicesConfig {
+	return func(s *Services) error {
+		s.Interaction = NewInteractionService(s.db)
+		return nil
+	}
+}
+
+// WithAttendance will initialize the Attendance service
+func WithAttendance() ServicesConfig {
+	return func(s *Services) error {
+		s.Attendance = NewAttendanceService(s.db)
+		return nil
+	}
+}
+
+// WithLedger will initialize the completed trade Ledger service
+func WithLedger() ServicesConfig {
+	return func(s *Services) error {
+		s.Ledger = NewLedgerService(s.db)
+		return nil
+	}
+}
+
+// WithWishlists will initialize the item Wishlist service
+func WithWishlists() ServicesConfig {
+	return func(s *Services) error {
+		s.Wishlist = NewWishlistService(s.db)
+		return nil
+	}
+}
+
+// WithCatalog will initialize the items Catalog database
+func WithCatalog() ServicesConfig {
+	return func(s *Services) error {
+		s.Catalog = NewCatalogService(s.db)
+		return nil
+	}
+}
+
 // WithLogMode makes sure that every database interaction in logged whether
 // for debugging or other logging purposes
 func WithLogMode(mode bool) ServicesConfig {
@@ -111,5 +232,5 @@ func (s Services) Close() error {
 
 // AutoMigrate attempts to automigrate sql tables
 func (s Services) AutoMigrate() error {
-	return s.db.AutoMigrate(&Rep{}).Error
+	return s.db.AutoMigrate(&Rep{}, &Profile{}, &Block{}, &MinRep{}, &EventRecord{}, &Visit{}, &Template{}, &Subscription{}, &Interaction{}, &Attendance{}, &TradeRecord{}, &Wish{}).Error
 }
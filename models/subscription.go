@@ -0,0 +1,170 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// SubEvent subscriptions match new events by event type
+	SubEvent string = "event"
+
+	// SubTrade subscriptions match new trades whose item contains the keyword
+	SubTrade string = "trade"
+
+	// MaxSubscriptions limits the amount of subscriptions a user can have
+	MaxSubscriptions int = 10
+
+	// ErrNotSubscribed is returned when a user unsubscribes from something
+	// they never subscribed to
+	ErrNotSubscribed string = "you are not subscribed to that"
+)
+
+// Subscription defines the postgres SQL table model of listings users want
+// to be messaged about using GORM
+type Subscription struct {
+	gorm.Model
+
+	// Discord ID of the subscriber
+	UserID string `gorm:"not_null;index"`
+
+	// What the subscription matches (event or trade)
+	Kind string `gorm:"not_null"`
+
+	// Event type or lower case trade item keyword
+	Keyword string `gorm:"not_null"`
+}
+
+// SubscriptionService wraps to SubscriptionDB
+type SubscriptionService interface {
+	SubscriptionDB
+}
+
+// SubscriptionDB contains all methods we can use to interact with the
+// subscription database
+type SubscriptionDB interface {
+	// Subscribe saves a new subscription for the user
+	Subscribe(userID, kind, keyword string) error
+
+	// Unsubscribe removes one of the user's subscriptions
+	Unsubscribe(userID, kind, keyword string) error
+
+	// UnsubscribeAll removes every subscription of the user
+	UnsubscribeAll(userID string) error
+
+	// ByUser returns every subscription of the user
+	ByUser(userID string) []Subscription
+
+	// Events returns the subscriptions matching a new event of an event type
+	Events(eventType string) []Subscription
+
+	// Trades returns the subscriptions matching a new trade of an item
+	Trades(item string) []Subscription
+}
+
+type subscriptionGorm struct {
+	// gorm database connection
+	db *gorm.DB
+}
+
+type subscriptionService struct {
+	SubscriptionDB
+}
+
+type subscriptionValidator struct {
+	SubscriptionDB
+}
+
+var _ SubscriptionDB = &subscriptionGorm{}
+
+// NewSubscriptionService creates the subscription service object
+func NewSubscriptionService(db *gorm.DB) SubscriptionService {
+	return &subscriptionService{
+		SubscriptionDB: &subscriptionValidator{
+			SubscriptionDB: &subscriptionGorm{
+				db: db,
+			},
+		},
+	}
+}
+
+// Subscribe makes sure the subscription is complete, new and the user
+// has room for it
+func (sv *subscriptionValidator) Subscribe(userID, kind, keyword string) error {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	if userID == "" {
+		return errors.New(ErrDiscordIDRequired)
+	}
+	if kind != SubEvent && kind != SubTrade {
+		return errors.New("you can only subscribe to events or trades")
+	}
+	if keyword == "" {
+		return errors.New("need something to subscribe to")
+	}
+	subs := sv.SubscriptionDB.ByUser(userID)
+	for _, s := range subs {
+		if s.Kind == kind && s.Keyword == keyword {
+			return errors.New("you are already subscribed to that")
+		}
+	}
+	if len(subs) >= MaxSubscriptions {
+		return errors.New("you already have the max amount of subscriptions")
+	}
+	return sv.SubscriptionDB.Subscribe(userID, kind, keyword)
+}
+
+// Unsubscribe makes sure the user has the subscription before removing it
+func (sv *subscriptionValidator) Unsubscribe(userID, kind, keyword string) error {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	for _, s := range sv.SubscriptionDB.ByUser(userID) {
+		if s.Kind == kind && s.Keyword == keyword {
+			return sv.SubscriptionDB.Unsubscribe(userID, kind, keyword)
+		}
+	}
+	return errors.New(ErrNotSubscribed)
+}
+
+// Trades makes sure the item is matched in lower case
+func (sv *subscriptionValidator) Trades(item string) []Subscription {
+	return sv.SubscriptionDB.Trades(strings.ToLower(item))
+}
+
+// Subscribe saves a new subscription for the user
+func (sg *subscriptionGorm) Subscribe(userID, kind, keyword string) error {
+	return sg.db.Create(&Subscription{UserID: userID, Kind: kind, Keyword: keyword}).Error
+}
+
+// Unsubscribe removes one of the user's subscriptions
+func (sg *subscriptionGorm) Unsubscribe(userID, kind, keyword string) error {
+	return sg.db.Unscoped().
+		Where("user_id = ? AND kind = ? AND keyword = ?", userID, kind, keyword).
+		Delete(&Subscription{}).Error
+}
+
+// UnsubscribeAll removes every subscription of the user
+func (sg *subscriptionGorm) UnsubscribeAll(userID string) error {
+	return sg.db.Unscoped().Where("user_id = ?", userID).Delete(&Subscription{}).Error
+}
+
+// ByUser returns every subscription of the user
+func (sg *subscriptionGorm) ByUser(userID string) []Subscription {
+	var ret []Subscription
+	sg.db.Where("user_id = ?", userID).Order("kind, keyword").Find(&ret)
+	return ret
+}
+
+// Events returns the subscriptions matching a new event of an event type
+func (sg *subscriptionGorm) Events(eventType string) []Subscription {
+	var ret []Subscription
+	sg.db.Where("kind = ? AND keyword = ?", SubEvent, eventType).Find(&ret)
+	return ret
+}
+
+// Trades returns the subscriptions whose keyword is part of the item
+func (sg *subscriptionGorm) Trades(item string) []Subscription {
+	var ret []Subscription
+	sg.db.Where("kind = ? AND strpos(?, keyword) > 0", SubTrade, item).Find(&ret)
+	return ret
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// ErrTemplateNotFound is returned when a host has no template by that name
+	ErrTemplateNotFound string = "template not found"
+
+	// MaxTemplates limits the amount of templates a host can save
+	MaxTemplates int = 10
+)
+
+// templateName matches valid template names
+var templateName = regexp.MustCompile(`^[a-z0-9_-]{1,20}$`)
+
+// Template defines the postgres SQL table model of saved events hosts can
+// re-launch or repeat weekly using GORM
+type Template struct {
+	gorm.Model
+
+	// Discord ID of the host who saved the template
+	HostID string `gorm:"not_null;index"`
+
+	// Name the host gave the template (lower case)
+	Name string `gorm:"not_null"`
+
+	// Event keyword (e.g. diy)
+	EventType string `gorm:"not_null"`
+
+	// Event arguments as key="value" pairs
+	Args string `gorm:"not_null"`
+
+	// Weekdays the event repeats on as a bit mask (1 << time.Weekday);
+	// 0 means it doesn't repeat
+	Days int
+
+	// Time of day (15:04) the repeated queue opens in the host's timezone
+	OpensAt string
+
+	// Next time the repeated queue opens
+	NextRun time.Time
+}
+
+// TemplateService wraps to TemplateDB
+type TemplateService interface {
+	TemplateDB
+}
+
+// TemplateDB contains all methods we can use to interact with the
+// template database
+type TemplateDB interface {
+	// Save creates or replaces a host's template
+	Save(template *Template) error
+
+	// Get returns a host's template by name
+	Get(hostID, name string) (*Template, error)
+
+	// ByHost returns every template a host saved
+	ByHost(hostID string) []Template
+
+	// Delete removes a host's template
+	Delete(hostID, name string) error
+
+	// SetRepeat sets the weekly pattern of a template; days of 0 stops it
+	// from repeating
+	SetRepeat(hostID, name string, days int, at string, next time.Time) error
+
+	// Due returns repeating templates that open at or before the given time
+	Due(before time.Time) []Template
+
+	// SetNext records the next time a repeating template opens
+	SetNext(id uint, next time.Time) error
+}
+
+type templateGorm struct {
+	// gorm database connection
+	db *gorm.DB
+}
+
+type templateService struct {
+	TemplateDB
+}
+
+type templateValidator struct {
+	TemplateDB
+}
+
+var _ TemplateDB = &templateGorm{}
+
+// NewTemplateService creates the template service object
+func NewTemplateService(db *gorm.DB) TemplateService {
+	return &templateService{
+		TemplateDB: &templateValidator{
+			TemplateDB: &templateGorm{
+				db: db,
+			},
+		},
+	}
+}
+
+// Save makes sure the template has a valid name and the host has room
+// for a new template
+func (tv *templateValidator) Save(template *Template) error {
+	if template.HostID == "" {
+		return errors.New(ErrDiscordIDRequired)
+	}
+	if !templateName.MatchString(template.Name) {
+		return errors.New("template names can only use up to 20 letters, numbers, - and _")
+	}
+	if _, err := tv.TemplateDB.Get(template.HostID, template.Name); err != nil &&
+		len(tv.TemplateDB.ByHost(template.HostID)) >= MaxTemplates {
+		return errors.New("you already have the max amount of templates")
+	}
+	return tv.TemplateDB.Save(template)
+}
+
+// SetRepeat makes sure the template exists and the pattern is complete
+func (tv *templateValidator) SetRepeat(hostID, name string, days int, at string, next time.Time) error {
+	if _, err := tv.TemplateDB.Get(hostID, name); err != nil {
+		return err
+	}
+	if days != 0 && at == "" {
+		return errors.New("need a time of day")
+	}
+	return tv.TemplateDB.SetRepeat(hostID, name, days, at, next)
+}
+
+// Delete makes sure the template exists before removing it
+func (tv *templateValidator) Delete(hostID, name string) error {
+	if _, err := tv.TemplateDB.Get(hostID, name); err != nil {
+		return err
+	}
+	return tv.TemplateDB.Delete(hostID, name)
+}
+
+// Save creates or replaces a host's template; the weekly pattern of an
+// existing template is kept
+func (tg *templateGorm) Save(template *Template) error {
+	var t Template
+	return tg.db.Where(Template{HostID: template.HostID, Name: template.Name}).
+		Assign(Template{EventType: template.EventType, Args: template.Args}).
+		FirstOrCreate(&t).Error
+}
+
+// Get returns a host's template by name
+func (tg *templateGorm) Get(hostID, name string) (*Template, error) {
+	var t Template
+	db := tg.db.Where("host_id = ? AND name = ?", hostID, name)
+	if err := first(db, &t); err != nil {
+		if err.Error() == ErrNotFound {
+			return nil, errors.New(ErrTemplateNotFound)
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ByHost returns every template a host saved
+func (tg *templateGorm) ByHost(hostID string) []Template {
+	var ret []Template
+	tg.db.Where("host_id = ?", hostID).Order("name").Find(&ret)
+	return ret
+}
+
+// Delete removes a host's template
+func (tg *templateGorm) Delete(hostID, name string) error {
+	return tg.db.Unscoped().Where("host_id = ? AND name = ?", hostID, name).Delete(&Template{}).Error
+}
+
+// SetRepeat sets the weekly pattern of a template
+func (tg *templateGorm) SetRepeat(hostID, name string, days int, at string, next time.Time) error {
+	return tg.db.Model(&Template{}).
+		Where("host_id = ? AND name = ?", hostID, name).
+		Updates(map[string]interface{}{"days": days, "opens_at": at, "next_run": next}).Error
+}
+
+// Due returns repeating templates that open at or before the given time
+func (tg *templateGorm) Due(before time.Time) []Template {
+	var ret []Template
+	tg.db.Where("days <> 0 AND next_run <= ?", before).Find(&ret)
+	return ret
+}
+
+// SetNext records the next time a repeating template opens
+func (tg *templateGorm) SetNext(id uint, next time.Time) error {
+	return tg.db.Model(&Template{}).Where("id = ?", id).Update("next_run", next).Error
+}
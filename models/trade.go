@@ -2,12 +2,40 @@ package models
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
+const (
+	// TradeFT listings offer the host's items for trade and TradeLF listings
+	// ask for items
+	TradeFT string = "ft"
+	TradeLF string = "lf"
+
+	// LineHave and LineWant are the sides of a trade listing lines can be on
+	LineHave string = "have"
+	LineWant string = "want"
+
+	// States of a trade offer; pending and countered offers are open
+	OfferPending   string = "pending"
+	OfferCountered string = "countered"
+	OfferAccepted  string = "accepted"
+	OfferDeclined  string = "declined"
+	OfferWithdrawn string = "withdrawn"
+
+	// SnipeWindow is how close to the end of an auction a bid has to land
+	// to extend the auction; the auction then ends SnipeWindow after the bid
+	SnipeWindow = 2 * time.Minute
+)
+
+// TradeCategories are the categories a trade can be listed under
+var TradeCategories = []string{"diy", "furniture", "materials", "villager", "turnips", "tickets"}
+
 // TradeService wraps to the Trade interface
 type TradeService interface {
 	Trade
@@ -27,11 +55,12 @@ type Trade interface {
 
 	// AddOffer will track an offer to a tradeID
 	//
-	// This func will return an err if the user is already in trade, else nil
-	AddOffer(tradeID, offer string, user *discordgo.User) error
+	// This func will return an err if the user is already in trade or if rep
+	// doesn't meet the trade's minimum, else nil
+	AddOffer(tradeID, offer string, lines []TradeLine, rep int, user *discordgo.User) error
 
 	// AddTrade will add a new trade event to tracking
-	AddTrade(tradeID string, user *discordgo.User)
+	AddTrade(tradeID string, trade *TradeData)
 
 	// Exists returns true if an event with the trade ID exists
 	Exists(tradeID string) bool
@@ -43,24 +72,128 @@ type Trade interface {
 	// will return an error
 	Close(tradeID string, user *discordgo.User, userRoles []string, adminID string) error
 
-	// Remove removes a user's offer from a tradeID
-	Remove(tradeID string, user *discordgo.User)
-
 	// GetAllOffers will return a slice of all trade offers associated with the tradeID
 	GetAllOffers(tradeID string) []TradeOfferer
+
+	// FindOffer returns a copy of a user's offer to a trade; false if the
+	// user never offered
+	FindOffer(tradeID, userID string) (TradeOfferer, bool)
+
+	// UpdateOffer moves an open offer to a new state; counter is the host's
+	// counter-offer and only used for the countered state
+	//
+	// This func will return an err if the offer isn't open or the state is
+	// unknown, else the updated offer
+	UpdateOffer(tradeID, userID, state, counter string) (TradeOfferer, error)
+
+	// EditOffer replaces an open offer and keeps the old one in its history;
+	// the edited offer is pending again
+	//
+	// This func will return an err if the offer isn't open or is unchanged,
+	// else the edited offer
+	EditOffer(tradeID, userID, offer string, lines []TradeLine) (TradeOfferer, error)
+
+	// GetTrade returns a copy of a trade's data
+	GetTrade(tradeID string) TradeData
+
+	// All returns copies of every active trade sorted by expiration
+	All() []TradeData
+
+	// Search returns copies of every active trade whose item or message
+	// contains the keyword, sorted by expiration
+	Search(keyword string) []TradeData
+
+	// SearchLines returns copies of every active trade with a line on the
+	// side (have or want) whose item contains the keyword, sorted by
+	// expiration
+	SearchLines(side, keyword string) []TradeData
+
+	// Bid places a bell bid on an auction trade
+	//
+	// This func will return an err if the trade isn't a running auction, the
+	// bid is too low or the user can't bid; else whether the bid extended
+	// the auction
+	Bid(tradeID string, amount, rep int, user *discordgo.User) (bool, error)
+
+	// EndAuctions removes every auction whose end passed and returns copies
+	// of them
+	EndAuctions() []TradeData
+
+	// SetListing records where the trade's listing message was posted
+	SetListing(tradeID, channelID, messageID string)
 }
 
 // TradeData represents all data needed to keep
 // track of a trade event
 type TradeData struct {
+	// ID of the trade
+	ID string
+
 	// User info of trade host
 	DiscordUser *discordgo.User
 
+	// item the host is trading and their message; Item summarizes Have
+	// (or Want for LF listings)
+	Item string
+	Msg  string
+
+	// whether the host has items for trade (ft) or is looking for them (lf)
+	Direction string
+
+	// lines the host has to trade and wants in return
+	Have []TradeLine
+	Want []TradeLine
+
+	// reputation users need to offer to the trade
+	MinRep int
+
+	// optional category (one of TradeCategories) used to route the listing
+	Category string
+
+	// sealed offers are only shown to the host and mods
+	Sealed bool
+
 	// time the trade event will expire
 	Expiration time.Time
 
 	// slice of offer related data associated with tradeID
 	Offers []TradeOfferer
+
+	// Auction trades take bell bids until Ends instead of offers
+	Auction   bool
+	StartBid  int
+	Increment int
+	Ends      time.Time
+
+	// bids in the order they were placed; the last one is the highest
+	Bids []Bid
+
+	// Location of the listing message
+	ChannelID string
+	MessageID string
+}
+
+// Bid is a bell bid on an auction trade
+type Bid struct {
+	User   *discordgo.User
+	Amount int
+	At     time.Time
+}
+
+// HighBid returns the highest bid of an auction; false if nobody bid yet
+func (t TradeData) HighBid() (Bid, bool) {
+	if len(t.Bids) == 0 {
+		return Bid{}, false
+	}
+	return t.Bids[len(t.Bids)-1], true
+}
+
+// MinBid returns the lowest amount the next bid of an auction can be
+func (t TradeData) MinBid() int {
+	if high, ok := t.HighBid(); ok {
+		return high.Amount + t.Increment
+	}
+	return t.StartBid
 }
 
 // TradeOfferer defines someone offering a response to a trade
@@ -70,6 +203,41 @@ type TradeOfferer struct {
 
 	// What the user is offering in string format
 	Offer string
+
+	// What the user is offering as lines
+	Lines []TradeLine
+
+	// State of the offer (pending, countered, accepted, declined or withdrawn)
+	State string
+
+	// Latest counter-offer of the host
+	Counter string
+
+	// Earlier versions of the offer, oldest first
+	History []OfferRevision
+}
+
+// OfferRevision is an earlier version of an offer which was edited
+type OfferRevision struct {
+	Offer string
+
+	// when the offer was replaced
+	At time.Time
+}
+
+// Open returns true if the offer can still be countered, accepted or
+// declined
+func (o TradeOfferer) Open() bool {
+	return o.State == OfferPending || o.State == OfferCountered
+}
+
+// TradeLine is one item of a trade or offer and how many of it
+type TradeLine struct {
+	Item     string
+	Quantity int
+
+	// catalog ID of the item; 0 if the item isn't in the catalog
+	ItemID uint
 }
 
 type tradeStore struct {
@@ -86,12 +254,108 @@ type tradeService struct {
 
 var _ Trade = &tradeStore{}
 
+// GetTrade returns a copy of a trade's data
+func (ts tradeStore) GetTrade(tradeID string) TradeData {
+	ts.m.RLock()
+	defer ts.m.RUnlock()
+	val, ok := ts.ts[tradeID]
+	if !ok {
+		return TradeData{}
+	}
+	return copyTrade(val)
+}
+
+// copyTrade returns a copy of a trade's data which is safe to use after the
+// lock is released; the caller must hold the lock
+func copyTrade(val *TradeData) TradeData {
+	ret := *val
+	ret.Have = append([]TradeLine(nil), val.Have...)
+	ret.Want = append([]TradeLine(nil), val.Want...)
+	ret.Offers = copyOffers(val.Offers)
+	ret.Bids = append([]Bid(nil), val.Bids...)
+	return ret
+}
+
+// copyOffers returns copies of offers including their lines and history; the
+// caller must hold the lock
+func copyOffers(offers []TradeOfferer) []TradeOfferer {
+	var ret []TradeOfferer
+	for _, o := range offers {
+		ret = append(ret, copyOffer(o))
+	}
+	return ret
+}
+
+// copyOffer returns a copy of an offer including its lines and history
+func copyOffer(o TradeOfferer) TradeOfferer {
+	o.Lines = append([]TradeLine(nil), o.Lines...)
+	o.History = append([]OfferRevision(nil), o.History...)
+	return o
+}
+
+// All returns copies of every active trade sorted by expiration
+func (ts tradeStore) All() []TradeData {
+	return ts.filter(func(t *TradeData) bool { return true })
+}
+
+// Search returns copies of every active trade whose item, message or
+// lines contain the keyword, sorted by expiration
+func (ts tradeStore) Search(keyword string) []TradeData {
+	keyword = strings.ToLower(keyword)
+	return ts.filter(func(t *TradeData) bool {
+		return strings.Contains(strings.ToLower(t.Item), keyword) ||
+			strings.Contains(strings.ToLower(t.Msg), keyword) ||
+			containsLine(t.Have, keyword) || containsLine(t.Want, keyword)
+	})
+}
+
+// SearchLines returns copies of every active trade with a line on the side
+// (have or want) whose item contains the keyword, sorted by expiration
+func (ts tradeStore) SearchLines(side, keyword string) []TradeData {
+	keyword = strings.ToLower(keyword)
+	return ts.filter(func(t *TradeData) bool {
+		if side == LineWant {
+			return containsLine(t.Want, keyword)
+		}
+		return containsLine(t.Have, keyword)
+	})
+}
+
+// containsLine returns true if the item of any line contains the keyword
+func containsLine(lines []TradeLine, keyword string) bool {
+	for _, l := range lines {
+		if strings.Contains(strings.ToLower(l.Item), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns copies of the trades matching keep sorted by expiration
+func (ts tradeStore) filter(keep func(*TradeData) bool) []TradeData {
+	ts.m.RLock()
+	var ret []TradeData
+	for _, v := range ts.ts {
+		if keep(v) {
+			ret = append(ret, copyTrade(v))
+		}
+	}
+	ts.m.RUnlock()
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Expiration.Before(ret[j].Expiration)
+	})
+	return ret
+}
+
 // GetAllOffers will return a slice of all trade offers associated with the tradeID
 func (ts tradeStore) GetAllOffers(tradeID string) []TradeOfferer {
 	ts.m.RLock()
 	defer ts.m.RUnlock()
-	val := ts.ts[tradeID]
-	return val.Offers
+	val, ok := ts.ts[tradeID]
+	if !ok {
+		return nil
+	}
+	return copyOffers(val.Offers)
 }
 
 // GetOffer retrieves a trade offer by tradeID and userID
@@ -108,35 +372,21 @@ func (ts tradeStore) GetOffer(tradeID, userID string) string {
 }
 
 // Clean will remove an expired item from the map
+//
+// Auctions are left for EndAuctions so their winner can be announced
 func (ts tradeStore) Clean() {
 	ts.m.Lock()
 	defer ts.m.Unlock()
 	for k, v := range ts.ts {
+		if v.Auction {
+			continue
+		}
 		if time.Now().Sub(v.Expiration) > 0 {
 			delete(ts.ts, k)
 		}
 	}
 }
 
-// Remove removes a user's offer from a tradeID
-func (ts tradeStore) Remove(tradeID string, user *discordgo.User) {
-	ts.m.Lock()
-	defer ts.m.Unlock()
-	ts.ts[tradeID].Offers = removeOffer(user, ts.ts[tradeID].Offers)
-}
-
-// utility func to remove an offer from a slice of offers based on discord user
-func removeOffer(user *discordgo.User, offers []TradeOfferer) []TradeOfferer {
-	var ret []TradeOfferer
-	for _, val := range offers {
-		if val.User.ID == user.ID {
-			continue
-		}
-		ret = append(ret, val)
-	}
-	return ret
-}
-
 // GetExpiration returns the expiration time of the trade event
 func (ts tradeStore) GetExpiration(tradeID string) time.Time {
 	ts.m.RLock()
@@ -146,33 +396,126 @@ func (ts tradeStore) GetExpiration(tradeID string) time.Time {
 
 // AddOffer will track an offer to a tradeID
 //
-// This func will return an err if the user is already in trade, else nil
-func (ts tradeStore) AddOffer(tradeID, tradeOffer string, user *discordgo.User) error {
+// This func will return an err if the user is already in trade or if rep
+// doesn't meet the trade's minimum, else nil
+func (ts tradeStore) AddOffer(tradeID, tradeOffer string, lines []TradeLine, rep int, user *discordgo.User) error {
 	ts.m.Lock()
 	defer ts.m.Unlock()
 	new := TradeOfferer{
 		User:  user,
 		Offer: tradeOffer,
+		Lines: lines,
+		State: OfferPending,
 	}
-	val := ts.ts[tradeID]
-	if containsUser(user, val.Offers) {
-		return errors.New("user already in trade")
+	val, ok := ts.ts[tradeID]
+	if !ok {
+		return errors.New("trade does not exist")
+	}
+	if val.Auction {
+		return errors.New("this trade is an auction; place a bid instead")
 	}
 	if val.DiscordUser.ID == user.ID {
 		return errors.New("you cannot offer for your own trade")
 	}
-	ts.ts[tradeID].Offers = append(ts.ts[tradeID].Offers, new)
-	return nil
+	if err := meetsMinRep(val.MinRep, rep, "trade"); err != nil {
+		return err
+	}
+	i := offerIndex(user.ID, val.Offers)
+	if i < 0 {
+		val.Offers = append(val.Offers, new)
+		return nil
+	}
+	switch val.Offers[i].State {
+	case OfferDeclined, OfferWithdrawn:
+		// declined and withdrawn offers are replaced by the new offer
+		val.Offers[i] = new
+		return nil
+	case OfferAccepted:
+		return errors.New("your offer was already accepted")
+	}
+	return errors.New("user already in trade")
 }
 
-// containsUser returns true if a user is found in the slice of offerers
-func containsUser(user *discordgo.User, offers []TradeOfferer) bool {
-	for _, u := range offers {
-		if user.ID == u.User.ID {
-			return true
+// offerIndex returns the index of a user's offer in the slice of offerers;
+// -1 if the user never offered
+func offerIndex(userID string, offers []TradeOfferer) int {
+	for i, o := range offers {
+		if o.User.ID == userID {
+			return i
 		}
 	}
-	return false
+	return -1
+}
+
+// FindOffer returns a copy of a user's offer to a trade
+func (ts tradeStore) FindOffer(tradeID, userID string) (TradeOfferer, bool) {
+	ts.m.RLock()
+	defer ts.m.RUnlock()
+	val, ok := ts.ts[tradeID]
+	if !ok {
+		return TradeOfferer{}, false
+	}
+	if i := offerIndex(userID, val.Offers); i >= 0 {
+		return copyOffer(val.Offers[i]), true
+	}
+	return TradeOfferer{}, false
+}
+
+// UpdateOffer moves an open offer to a new state
+func (ts tradeStore) UpdateOffer(tradeID, userID, state, counter string) (TradeOfferer, error) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	val, ok := ts.ts[tradeID]
+	if !ok {
+		return TradeOfferer{}, errors.New("trade does not exist")
+	}
+	i := offerIndex(userID, val.Offers)
+	if i < 0 {
+		return TradeOfferer{}, errors.New("this user hasn't made an offer")
+	}
+	o := &val.Offers[i]
+	if !o.Open() {
+		return TradeOfferer{}, errors.New("this offer was already " + o.State)
+	}
+	switch state {
+	case OfferCountered:
+		if counter == "" {
+			return TradeOfferer{}, errors.New("need a counter-offer")
+		}
+		o.Counter = counter
+	case OfferAccepted, OfferDeclined, OfferWithdrawn:
+	default:
+		return TradeOfferer{}, errors.New("unknown offer state")
+	}
+	o.State = state
+	return copyOffer(*o), nil
+}
+
+// EditOffer replaces an open offer and keeps the old one in its history
+func (ts tradeStore) EditOffer(tradeID, userID, offer string, lines []TradeLine) (TradeOfferer, error) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	val, ok := ts.ts[tradeID]
+	if !ok {
+		return TradeOfferer{}, errors.New("trade does not exist")
+	}
+	i := offerIndex(userID, val.Offers)
+	if i < 0 {
+		return TradeOfferer{}, errors.New("you haven't made an offer")
+	}
+	o := &val.Offers[i]
+	if !o.Open() {
+		return TradeOfferer{}, errors.New("this offer was already " + o.State)
+	}
+	if strings.EqualFold(o.Offer, offer) {
+		return TradeOfferer{}, errors.New("this is already your offer")
+	}
+	o.History = append(o.History, OfferRevision{Offer: o.Offer, At: time.Now()})
+	o.Offer = offer
+	o.Lines = lines
+	o.State = OfferPending
+	o.Counter = ""
+	return copyOffer(*o), nil
 }
 
 // Close will close a trade event. If the user does not have permission to close the event, the func
@@ -197,16 +540,83 @@ func (ts tradeStore) GetHost(tradeID string) *discordgo.User {
 }
 
 // AddTrade will add a new trade event to tracking
-func (ts tradeStore) AddTrade(tradeID string, user *discordgo.User) {
+func (ts tradeStore) AddTrade(tradeID string, trade *TradeData) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	trade.ID = tradeID
+	trade.Expiration = time.Now().Add(4 * time.Hour)
+	if trade.Auction {
+		trade.Expiration = trade.Ends
+	}
+	trade.Offers = make([]TradeOfferer, 0)
+	trade.Bids = nil
+	ts.ts[tradeID] = trade
+}
+
+// Bid places a bell bid on an auction trade
+//
+// Bids in the last SnipeWindow of an auction push the end back so everyone
+// gets a chance to respond
+func (ts tradeStore) Bid(tradeID string, amount, rep int, user *discordgo.User) (bool, error) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	val, ok := ts.ts[tradeID]
+	if !ok {
+		return false, errors.New("trade does not exist")
+	}
+	if !val.Auction {
+		return false, errors.New("this trade isn't an auction; make an offer instead")
+	}
+	now := time.Now()
+	if !now.Before(val.Ends) {
+		return false, errors.New("this auction has ended")
+	}
+	if val.DiscordUser.ID == user.ID {
+		return false, errors.New("you cannot bid on your own auction")
+	}
+	if err := meetsMinRep(val.MinRep, rep, "auction"); err != nil {
+		return false, err
+	}
+	if high, ok := val.HighBid(); ok && high.User.ID == user.ID {
+		return false, errors.New("you already have the highest bid")
+	}
+	if amount < val.MinBid() {
+		return false, fmt.Errorf("your bid must be at least %d bells", val.MinBid())
+	}
+	val.Bids = append(val.Bids, Bid{User: user, Amount: amount, At: now})
+	if val.Ends.Sub(now) < SnipeWindow {
+		val.Ends = now.Add(SnipeWindow)
+		val.Expiration = val.Ends
+		return true, nil
+	}
+	return false, nil
+}
+
+// EndAuctions removes every auction whose end passed and returns copies
+// of them
+func (ts tradeStore) EndAuctions() []TradeData {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	var ret []TradeData
+	now := time.Now()
+	for k, v := range ts.ts {
+		if !v.Auction || now.Before(v.Ends) {
+			continue
+		}
+		ret = append(ret, copyTrade(v))
+		delete(ts.ts, k)
+	}
+	return ret
+}
+
+// SetListing records where the trade's listing message was posted
+func (ts tradeStore) SetListing(tradeID, channelID, messageID string) {
 	ts.m.Lock()
 	defer ts.m.Unlock()
-	o := make([]TradeOfferer, 0)
-	new := TradeData{
-		DiscordUser: user,
-		Expiration:  time.Now().Add(4 * time.Hour),
-		Offers:      o,
+	if val, ok := ts.ts[tradeID]; ok {
+		val.ChannelID = channelID
+		val.MessageID = messageID
 	}
-	ts.ts[tradeID] = &new
 }
 
 // Exists returns true if an event with the trade ID exists
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	// MaxWishes limits the amount of items a user can have on their wishlist
+	MaxWishes int = 20
+
+	// ErrNotWished is returned when a user removes an item that isn't on
+	// their wishlist
+	ErrNotWished string = "that item isn't on your wishlist"
+)
+
+// Wish defines the postgres SQL table model of items users are looking
+// for using GORM
+type Wish struct {
+	gorm.Model
+
+	// Discord ID of the user
+	UserID string `gorm:"not_null;index"`
+
+	// Lower case item name
+	Item string `gorm:"not_null"`
+}
+
+// WishlistService wraps to WishlistDB
+type WishlistService interface {
+	WishlistDB
+}
+
+// WishlistDB contains all methods we can use to interact with the
+// wishlist database
+type WishlistDB interface {
+	// Add puts an item on the user's wishlist
+	Add(userID, item string) error
+
+	// Remove takes an item off of the user's wishlist
+	Remove(userID, item string) error
+
+	// Clear removes every item on the user's wishlist
+	Clear(userID string) error
+
+	// ByUser returns every item on the user's wishlist
+	ByUser(userID string) []Wish
+
+	// All returns every wish of every user
+	All() []Wish
+}
+
+type wishlistGorm struct {
+	// gorm database connection
+	db *gorm.DB
+}
+
+type wishlistService struct {
+	WishlistDB
+}
+
+type wishlistValidator struct {
+	WishlistDB
+}
+
+var _ WishlistDB = &wishlistGorm{}
+
+// NewWishlistService creates the wishlist service object
+func NewWishlistService(db *gorm.DB) WishlistService {
+	return &wishlistService{
+		WishlistDB: &wishlistValidator{
+			WishlistDB: &wishlistGorm{
+				db: db,
+			},
+		},
+	}
+}
+
+// Add makes sure the item is new and the user has room for it
+func (wv *wishlistValidator) Add(userID, item string) error {
+	item = strings.ToLower(strings.TrimSpace(item))
+	if userID == "" {
+		return errors.New(ErrDiscordIDRequired)
+	}
+	if item == "" {
+		return errors.New("need an item to add")
+	}
+	wishes := wv.WishlistDB.ByUser(userID)
+	for _, w := range wishes {
+		if w.Item == item {
+			return errors.New("that item is already on your wishlist")
+		}
+	}
+	if len(wishes) >= MaxWishes {
+		return errors.New("your wishlist is full")
+	}
+	return wv.WishlistDB.Add(userID, item)
+}
+
+// Remove makes sure the item is on the user's wishlist before removing it
+func (wv *wishlistValidator) Remove(userID, item string) error {
+	item = strings.ToLower(strings.TrimSpace(item))
+	for _, w := range wv.WishlistDB.ByUser(userID) {
+		if w.Item == item {
+			return wv.WishlistDB.Remove(userID, item)
+		}
+	}
+	return errors.New(ErrNotWished)
+}
+
+// Add puts an item on the user's wishlist
+func (wg *wishlistGorm) Add(userID, item string) error {
+	return wg.db.Create(&Wish{UserID: userID, Item: item}).Error
+}
+
+// Remove takes an item off of the user's wishlist
+func (wg *wishlistGorm) Remove(userID, item string) error {
+	return wg.db.Unscoped().Where("user_id = ? AND item = ?", userID, item).Delete(&Wish{}).Error
+}
+
+// Clear removes every item on the user's wishlist
+func (wg *wishlistGorm) Clear(userID string) error {
+	return wg.db.Unscoped().Where("user_id = ?", userID).Delete(&Wish{}).Error
+}
+
+// ByUser returns every item on the user's wishlist
+func (wg *wishlistGorm) ByUser(userID string) []Wish {
+	var ret []Wish
+	wg.db.Where("user_id = ?", userID).Order("item").Find(&ret)
+	return ret
+}
+
+// All returns every wish of every user
+func (wg *wishlistGorm) All() []Wish {
+	var ret []Wish
+	wg.db.Find(&ret)
+	return ret
+}